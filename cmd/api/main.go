@@ -11,9 +11,12 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/hamed0406/uptimechecker/internal/config"
+	"github.com/hamed0406/uptimechecker/internal/events"
 	"github.com/hamed0406/uptimechecker/internal/httpapi"
 	apimw "github.com/hamed0406/uptimechecker/internal/httpapi/middleware"
 	"github.com/hamed0406/uptimechecker/internal/logging"
+	"github.com/hamed0406/uptimechecker/internal/metrics"
+	"github.com/hamed0406/uptimechecker/internal/notify"
 	"github.com/hamed0406/uptimechecker/internal/probe"
 	"github.com/hamed0406/uptimechecker/internal/repo"
 	"github.com/hamed0406/uptimechecker/internal/repo/memory"
@@ -32,8 +35,16 @@ func main() {
 
 	var targets repo.TargetStore
 	var results repo.ResultStore
+	var alerts repo.AlertStore
+	var incidents repo.IncidentStore
+	var outboxStore repo.OutboxStore
 
-	base := probe.NewHTTPChecker(cfg.HTTPTimeout)
+	httpChk := probe.NewHTTPChecker(cfg.HTTPTimeout, probe.WithMaxBodyBytes(cfg.HTTPMaxBodyBytes))
+	base := probe.NewSchemeChecker(map[string]probe.Checker{
+		"http":  httpChk,
+		"https": probe.WithTLS(httpChk, probe.NewTLSChecker(cfg.HTTPTimeout, cfg.TLSExpiryWindow)),
+		"tcp":   probe.NewTCPChecker(cfg.HTTPTimeout),
+	})
 	chk := &probe.RetryChecker{
 		Inner:    base,
 		Attempts: cfg.RetryAttempts,
@@ -48,15 +59,32 @@ func main() {
 		defer pg.Close()
 		targets = pg
 		results = pg
+		alerts = pg
+		incidents = pg
+		outboxStore = pg
 		log.Info("repo_postgres_enabled")
 	} else {
 		mem := memory.New()
 		targets = mem
 		results = mem
+		alerts = mem
+		incidents = mem
+		outboxStore = mem
 		log.Info("repo_memory_enabled")
 	}
 
 	srv := httpapi.NewServer(log, targets, results, chk)
+	srv.Incidents = incidents
+	srv.Notifications = outboxStore
+	mets := metrics.New()
+	srv.Metrics = mets
+	if cfg.StatusPageEnabled {
+		srv.StatusPage = &httpapi.StatusPage{Title: cfg.StatusPageTitle, Public: cfg.StatusPagePublic}
+	}
+	srv.PublicBadges = cfg.PublicBadges
+	srv.CheckTimeout = cfg.HTTPTimeout
+	bus := events.NewBus()
+	srv.Events = bus
 
 	keys := apimw.Keys{
 		Public: cfg.PublicAPIKeys,
@@ -79,6 +107,41 @@ func main() {
 		cfg.HTTPTimeout,
 		cfg.MaxConcurrentRuns,
 	)
+	rechk.Incidents = incidents
+	rechk.DownAfter = cfg.AlertDownAfter
+	rechk.UpAfter = cfg.AlertUpAfter
+	rechk.Metrics = mets
+	rechk.Events = bus
+
+	alertRouter, err := buildRouter(cfg)
+	if err != nil {
+		log.Fatal("notifier_config_error", zap.Error(err))
+	}
+	// Alerts are queued in the outbox and delivered from there, so a
+	// notifier outage delays them instead of losing them.
+	outbox := scheduler.NewOutbox(outboxStore, alertRouter, scheduler.OutboxConfig{
+		PollInterval: cfg.OutboxPollInterval,
+		MaxAttempts:  cfg.OutboxMaxAttempts,
+		Backoff:      cfg.OutboxBackoff,
+		MaxBackoff:   cfg.OutboxMaxBackoff,
+	})
+	outbox.Metrics = mets
+	alerter := scheduler.NewAlerter(results, alerts, outbox, scheduler.AlerterConfig{
+		AlertOnRecovery: cfg.AlertOnRecovery,
+		Cooldown:        cfg.AlertCooldown,
+		PollInterval:    cfg.AlertPollInterval,
+		DownAfter:       cfg.AlertDownAfter,
+		UpAfter:         cfg.AlertUpAfter,
+		FlapWindow:      cfg.FlapWindow,
+		FlapThreshold:   cfg.FlapThreshold,
+		FlapSettle:      cfg.FlapSettle,
+	})
+	alerter.Metrics = mets
+	alerter.Targets = targets
+	alerter.Incidents = incidents
+	if alertRouter.HasEscalations() {
+		alerter.Escalation = outbox
+	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -87,11 +150,34 @@ func main() {
 		go rechk.Run(ctx)
 	}
 
+	switch {
+	case cfg.AlertPollInterval <= 0:
+		log.Info("alerter_disabled", zap.String("reason", "poll interval is 0"))
+	case alertRouter.Len() == 0:
+		log.Info("alerter_disabled", zap.String("reason", "no notifiers configured"))
+	default:
+		log.Info("alerter_enabled",
+			zap.Int("notifiers", alertRouter.Len()),
+			zap.Bool("routed", cfg.RoutingFile != ""),
+			zap.Bool("escalation", alerter.Escalation != nil),
+			zap.Duration("poll_interval", cfg.AlertPollInterval),
+			zap.Duration("cooldown", cfg.AlertCooldown),
+			zap.Bool("on_recovery", cfg.AlertOnRecovery),
+			zap.Int("down_after", cfg.AlertDownAfter),
+			zap.Int("up_after", cfg.AlertUpAfter),
+			zap.Int("outbox_max_attempts", cfg.OutboxMaxAttempts),
+		)
+		go func() { _ = outbox.Run(ctx) }()
+		go func() { _ = alerter.Run(ctx) }()
+	}
+
 	server := &http.Server{
 		Addr:              cfg.Addr,
 		Handler:           router,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
+	// SSE streams never finish on their own; end them so Shutdown need not wait.
+	server.RegisterOnShutdown(bus.Close)
 
 	go func() {
 		log.Info("api_listen", zap.String("addr", cfg.Addr))
@@ -106,3 +192,65 @@ func main() {
 	_ = server.Shutdown(shutdownCtx)
 	log.Info("api_stopped")
 }
+
+// buildRouter puts every configured notifier behind a router, using the
+// rules in ROUTING_FILE if set (else everything goes to every notifier).
+func buildRouter(cfg config.Config) (*notify.Router, error) {
+	notifiers, err := buildNotifiers(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RoutingFile == "" {
+		return notify.NewRouter(notify.RoutingConfig{}, notifiers)
+	}
+	return notify.LoadRouting(cfg.RoutingFile, notifiers)
+}
+
+// buildNotifiers returns every notifier that has been configured via env,
+// named by kind (webhooks as "webhook:<name>").
+func buildNotifiers(cfg config.Config) ([]notify.Named, error) {
+	var out []notify.Named
+	if s := notify.NewSlack(cfg.SlackWebhookURL); s != nil {
+		out = append(out, notify.Named{Name: "slack", Notifier: s})
+	}
+	if t := notify.NewTeams(cfg.TeamsWebhookURL); t != nil {
+		out = append(out, notify.Named{Name: "teams", Notifier: t})
+	}
+	if d := notify.NewDiscord(cfg.DiscordWebhookURL); d != nil {
+		out = append(out, notify.Named{Name: "discord", Notifier: d})
+	}
+	if t := notify.NewTelegram(cfg.TelegramBotToken, cfg.TelegramChatID); t != nil {
+		out = append(out, notify.Named{Name: "telegram", Notifier: t})
+	}
+	if p := notify.NewPagerDuty(cfg.PagerDutyRoutingKey); p != nil {
+		out = append(out, notify.Named{Name: "pagerduty", Notifier: p})
+	}
+	if o := notify.NewOpsgenie(cfg.OpsgenieAPIKey, cfg.OpsgenieAPIURL); o != nil {
+		out = append(out, notify.Named{Name: "opsgenie", Notifier: o})
+	}
+	if cfg.WebhooksFile != "" {
+		hooks, err := notify.LoadWebhooks(cfg.WebhooksFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hooks {
+			out = append(out, notify.Named{Name: "webhook:" + h.Name, Notifier: h})
+		}
+	}
+	if cfg.SMTPHost != "" {
+		e, err := notify.NewEmail(notify.EmailConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			To:       cfg.SMTPTo,
+			TLS:      cfg.SMTPTLS,
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, notify.Named{Name: "email", Notifier: e})
+	}
+	return out, nil
+}
@@ -1,7 +1,77 @@
 package main
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hamed0406/uptimechecker/internal/config"
+	"github.com/hamed0406/uptimechecker/internal/notify"
+)
 
 // We typically avoid spinning up the whole server in unit tests.
 // This placeholder keeps the package covered and compile-checked.
 func TestMainPackageCompiles(t *testing.T) {}
+
+func TestBuildNotifiers_OnlyConfigured(t *testing.T) {
+	if n, err := buildNotifiers(config.Config{}); err != nil || len(n) != 0 {
+		t.Fatalf("want no notifiers, got %d (%v)", len(n), err)
+	}
+	if n, err := buildNotifiers(config.Config{SlackWebhookURL: "https://hooks.slack.test/x"}); err != nil || len(n) != 1 {
+		t.Fatalf("want slack notifier, got %d (%v)", len(n), err)
+	}
+	chat := config.Config{
+		TeamsWebhookURL:   "https://teams.test/x",
+		DiscordWebhookURL: "https://discord.test/x",
+		TelegramBotToken:  "123:abc",
+		TelegramChatID:    "-100",
+	}
+	if n, err := buildNotifiers(chat); err != nil || len(n) != 3 {
+		t.Fatalf("want teams, discord and telegram notifiers, got %d (%v)", len(n), err)
+	}
+	if n, err := buildNotifiers(config.Config{PagerDutyRoutingKey: "rk", OpsgenieAPIKey: "k"}); err != nil || len(n) != 2 {
+		t.Fatalf("want pagerduty and opsgenie notifiers, got %d (%v)", len(n), err)
+	}
+}
+
+func TestBuildNotifiers_Webhooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	_ = os.WriteFile(path, []byte(`[{"name":"a","url":"https://a.test/hook"},{"name":"b","url":"https://b.test/hook"}]`), 0o600)
+	if n, err := buildNotifiers(config.Config{WebhooksFile: path}); err != nil || len(n) != 2 {
+		t.Fatalf("want 2 webhooks, got %d (%v)", len(n), err)
+	}
+	if _, err := buildNotifiers(config.Config{WebhooksFile: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Fatal("want error for missing webhooks file")
+	}
+}
+
+func TestBuildNotifiers_Email(t *testing.T) {
+	cfg := config.Config{SMTPHost: "smtp.test", SMTPFrom: "uptime@a.test", SMTPTo: []string{"ops@a.test"}}
+	if n, err := buildNotifiers(cfg); err != nil || len(n) != 1 {
+		t.Fatalf("want email notifier, got %d (%v)", len(n), err)
+	}
+	cfg.SMTPTo = nil
+	if _, err := buildNotifiers(cfg); err == nil {
+		t.Fatal("want error for missing recipients")
+	}
+}
+
+func TestBuildRouter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routing.json")
+	_ = os.WriteFile(path, []byte(`{
+		"notifiers": {"payments": {"type": "pagerduty", "routing_key": "rk"}},
+		"routes": [{"match": {"team": "payments"}, "notifiers": ["payments"]}]
+	}`), 0o600)
+	cfg := config.Config{SlackWebhookURL: "https://hooks.slack.test/x", RoutingFile: path}
+	r, err := buildRouter(cfg)
+	if err != nil || r.Len() != 2 {
+		t.Fatalf("want router over 2 notifiers, got %v (%v)", r, err)
+	}
+	if got := r.Route(notify.Event{Kind: notify.KindDown}); len(got) != 1 || got[0] != "slack" {
+		t.Fatalf("unlabelled alerts should go to the env notifiers, got %v", got)
+	}
+	cfg.RoutingFile = ""
+	if r, err := buildRouter(cfg); err != nil || r.Len() != 1 {
+		t.Fatalf("without routing want 1 notifier, got %v (%v)", r, err)
+	}
+}
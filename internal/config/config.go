@@ -18,11 +18,13 @@ type Config struct {
 	AdminAPIKeys  []string
 
 	// Probe + scheduler
-	HTTPTimeout       time.Duration // per request timeout
+	HTTPTimeout       time.Duration // default per-target request timeout
+	HTTPMaxBodyBytes  int64         // body bytes read for assertions (targets may override)
 	RetryAttempts     int
 	RetryBackoff      time.Duration
-	CheckInterval     time.Duration // how often the scheduler runs
+	CheckInterval     time.Duration // default per-target check interval; 0 disables the scheduler
 	MaxConcurrentRuns int
+	TLSExpiryWindow   time.Duration // certs expiring within this window mark the target degraded
 
 	// Rate limits
 	PublicRPM   int // requests/min for public routes
@@ -32,6 +34,52 @@ type Config struct {
 
 	// Database
 	DatabaseURL string // if set, use Postgres; else in-memory
+
+	// Alerting
+	AlertPollInterval time.Duration // how often the alerter scans latest results; 0 disables
+	AlertCooldown     time.Duration // min gap between repeated DOWN alerts
+	AlertOnRecovery   bool
+	AlertDownAfter    int // consecutive failures before alerting DOWN (targets may override)
+	AlertUpAfter      int // consecutive successes before alerting RECOVERED
+	FlapWindow        time.Duration
+	FlapThreshold     int    // transitions within FlapWindow that mark a target flapping; 0 disables
+	FlapSettle        int    // flapping ends once transitions within FlapWindow drop to this
+	SlackWebhookURL   string // if set, alerts are posted to Slack
+	TeamsWebhookURL   string // Microsoft Teams incoming webhook
+	DiscordWebhookURL string
+	TelegramBotToken  string // with TelegramChatID, alerts go to a Telegram chat
+	TelegramChatID    string
+	WebhooksFile      string // JSON list of outgoing webhooks (see notify.WebhookConfig)
+
+	// Email alerts (enabled when SMTPHost is set)
+	SMTPHost     string
+	SMTPPort     int // 0 picks 587, or 465 with SMTPTLS=tls
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTo       []string
+	SMTPTLS      string // starttls, tls or none
+
+	// Paging (DOWN triggers, RECOVERED resolves; deduplicated per target)
+	PagerDutyRoutingKey string // Events API v2 integration key
+	OpsgenieAPIKey      string
+	OpsgenieAPIURL      string // https://api.eu.opsgenie.com for the EU instance
+
+	// RoutingFile maps target labels to notifiers (see notify.RoutingConfig);
+	// without it every alert goes to every notifier above.
+	RoutingFile string
+
+	// Notification outbox: alerts are queued and delivered with retries
+	OutboxPollInterval time.Duration
+	OutboxMaxAttempts  int           // attempts before a notification is dead-lettered
+	OutboxBackoff      time.Duration // wait after the first failure, doubled per retry
+	OutboxMaxBackoff   time.Duration
+
+	// Status page
+	StatusPageEnabled bool
+	StatusPagePublic  bool // serve /status without an API key
+	StatusPageTitle   string
+	PublicBadges      bool // serve /badge/* without an API key
 }
 
 // FromEnv builds Config from environment with sensible defaults.
@@ -45,10 +93,12 @@ func FromEnv() Config {
 		AdminAPIKeys:  splitCSV(getenv("ADMIN_API_KEYS", "")),
 
 		HTTPTimeout:       msToDuration(getenv("HTTP_TIMEOUT_MS", "5000")),
+		HTTPMaxBodyBytes:  int64(atoi(getenv("HTTP_MAX_BODY_BYTES", "65536"))),
 		RetryAttempts:     atoi(getenv("RETRY_ATTEMPTS", "3")),
 		RetryBackoff:      msToDuration(getenv("RETRY_BACKOFF_MS", "300")),
 		CheckInterval:     msToDuration(getenv("CHECK_INTERVAL_MS", "60000")),
 		MaxConcurrentRuns: atoi(getenv("MAX_CONCURRENT_CHECKS", "10")),
+		TLSExpiryWindow:   time.Duration(atoi(getenv("TLS_EXPIRY_WARN_DAYS", "14"))) * 24 * time.Hour,
 
 		PublicRPM:   atoi(getenv("PUBLIC_RPM", "300")),
 		PublicBurst: atoi(getenv("PUBLIC_BURST", "150")),
@@ -56,6 +106,45 @@ func FromEnv() Config {
 		AdminBurst:  atoi(getenv("ADMIN_BURST", "30")),
 
 		DatabaseURL: getenv("DATABASE_URL", ""),
+
+		AlertPollInterval: msToDuration(getenv("ALERT_POLL_INTERVAL_MS", "30000")),
+		AlertCooldown:     msToDuration(getenv("ALERT_COOLDOWN_MS", "900000")),
+		AlertOnRecovery:   atob(getenv("ALERT_ON_RECOVERY", "true")),
+		AlertDownAfter:    atoi(getenv("ALERT_DOWN_AFTER", "1")),
+		AlertUpAfter:      atoi(getenv("ALERT_UP_AFTER", "1")),
+		FlapWindow:        msToDuration(getenv("ALERT_FLAP_WINDOW_MS", "900000")),
+		FlapThreshold:     atoi(getenv("ALERT_FLAP_THRESHOLD", "5")),
+		FlapSettle:        atoi(getenv("ALERT_FLAP_SETTLE", "1")),
+		SlackWebhookURL:   getenv("SLACK_WEBHOOK_URL", ""),
+		TeamsWebhookURL:   getenv("TEAMS_WEBHOOK_URL", ""),
+		DiscordWebhookURL: getenv("DISCORD_WEBHOOK_URL", ""),
+		TelegramBotToken:  getenv("TELEGRAM_BOT_TOKEN", ""),
+		TelegramChatID:    getenv("TELEGRAM_CHAT_ID", ""),
+		WebhooksFile:      getenv("WEBHOOKS_FILE", ""),
+
+		SMTPHost:     getenv("SMTP_HOST", ""),
+		SMTPPort:     atoi(getenv("SMTP_PORT", "0")),
+		SMTPUsername: getenv("SMTP_USERNAME", ""),
+		SMTPPassword: getenv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getenv("SMTP_FROM", ""),
+		SMTPTo:       splitCSV(getenv("SMTP_TO", "")),
+		SMTPTLS:      getenv("SMTP_TLS", "starttls"),
+
+		PagerDutyRoutingKey: getenv("PAGERDUTY_ROUTING_KEY", ""),
+		OpsgenieAPIKey:      getenv("OPSGENIE_API_KEY", ""),
+		OpsgenieAPIURL:      getenv("OPSGENIE_API_URL", "https://api.opsgenie.com"),
+
+		RoutingFile: getenv("ROUTING_FILE", ""),
+
+		OutboxPollInterval: msToDuration(getenv("OUTBOX_POLL_INTERVAL_MS", "5000")),
+		OutboxMaxAttempts:  atoi(getenv("OUTBOX_MAX_ATTEMPTS", "8")),
+		OutboxBackoff:      msToDuration(getenv("OUTBOX_BACKOFF_MS", "10000")),
+		OutboxMaxBackoff:   msToDuration(getenv("OUTBOX_MAX_BACKOFF_MS", "900000")),
+
+		StatusPageEnabled: atob(getenv("STATUS_PAGE_ENABLED", "true")),
+		StatusPagePublic:  atob(getenv("STATUS_PAGE_PUBLIC", "false")),
+		StatusPageTitle:   getenv("STATUS_PAGE_TITLE", "Service Status"),
+		PublicBadges:      atob(getenv("BADGES_PUBLIC", "false")),
 	}
 }
 
@@ -71,6 +160,11 @@ func atoi(s string) int {
 	return i
 }
 
+func atob(s string) bool {
+	b, _ := strconv.ParseBool(strings.TrimSpace(s))
+	return b
+}
+
 func splitCSV(s string) []string {
 	if s == "" {
 		return nil
@@ -54,3 +54,25 @@ func TestCheckResult_JSONRoundTrip(t *testing.T) {
 		t.Fatalf("latency mismatch: want=%v got=%v", want.LatencyMS, got.LatencyMS)
 	}
 }
+
+func TestHTTPOptions_Redacted(t *testing.T) {
+	o := &HTTPOptions{
+		Headers:     map[string]string{"Authorization": "secret", "X-Vault-Token": "s.abc", "User-Agent": "probe/1"},
+		BasicAuth:   &BasicAuth{Username: "u", Password: "p"},
+		BearerToken: "tok",
+	}
+	r := o.Redacted()
+	if r.Headers["Authorization"] != redacted || r.Headers["X-Vault-Token"] != redacted || r.Headers["User-Agent"] != "probe/1" {
+		t.Fatalf("headers not redacted correctly: %+v", r.Headers)
+	}
+	if r.BasicAuth.Password == "p" || r.BasicAuth.Username != "u" || r.BearerToken == "tok" {
+		t.Fatalf("credentials not redacted: %+v", r)
+	}
+	// original untouched
+	if o.Headers["Authorization"] != "secret" || o.BasicAuth.Password != "p" || o.BearerToken != "tok" {
+		t.Fatalf("original mutated: %+v", o)
+	}
+	if (*HTTPOptions)(nil).Redacted() != nil {
+		t.Fatalf("nil should stay nil")
+	}
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Incident is one outage of a target: it opens on the first failed check and
+// closes on the next successful one. EndedAt is nil while it is open.
+type Incident struct {
+	ID           int64      `json:"id"`
+	TargetID     TargetID   `json:"target_id"`
+	StartedAt    time.Time  `json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at"`
+	FirstReason  string     `json:"first_reason"`
+	FailedChecks int        `json:"failed_checks"`
+	// AckedAt is set once someone acknowledges the incident; it stops
+	// escalation.
+	AckedAt *time.Time `json:"acked_at,omitempty"`
+	AckedBy string     `json:"acked_by,omitempty"`
+	// Escalation counts the escalation tiers notified so far (0 = only the
+	// initial alert went out).
+	Escalation int `json:"escalation_level"`
+}
+
+// Open reports whether the incident is still ongoing.
+func (i *Incident) Open() bool { return i.EndedAt == nil }
@@ -1,13 +1,174 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type TargetID string
 
 type Target struct {
-	ID        TargetID  `json:"id"`
-	URL       string    `json:"url"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        TargetID     `json:"id"`
+	URL       string       `json:"url"`
+	CreatedAt time.Time    `json:"created_at"`
+	HTTP      *HTTPOptions `json:"http,omitempty"`
+	// IntervalMS and TimeoutMS override the global check interval/timeout (0 = use global).
+	IntervalMS int `json:"interval_ms,omitempty"`
+	TimeoutMS  int `json:"timeout_ms,omitempty"`
+	// Paused targets stay listed but are not checked (e.g. planned maintenance).
+	Paused bool `json:"paused"`
+	// Group is the section the target is listed under on the status page.
+	Group string `json:"group,omitempty"`
+	// StatusPage lists the target on a public status page; behind an API
+	// key the page shows every target.
+	StatusPage bool `json:"status_page"`
+	// Labels such as team=payments or env=prod select alert routes.
+	Labels map[string]string `json:"labels,omitempty"`
+	// DownAfter / UpAfter are how many consecutive failed / successful checks
+	// the alerter needs before declaring the target down / up again
+	// (0 = use the global setting).
+	DownAfter int `json:"down_after,omitempty"`
+	UpAfter   int `json:"up_after,omitempty"`
+	// Latency, if set, marks the target degraded while responses are slow.
+	Latency *LatencyThreshold `json:"latency,omitempty"`
+}
+
+// LatencyThreshold is e.g. "p95 over the last 5 checks above 800ms":
+// Percentile of the latency of the last Samples successful checks must stay
+// at or below MaxMS.
+type LatencyThreshold struct {
+	Percentile float64 `json:"percentile"`
+	Samples    int     `json:"samples"`
+	MaxMS      float64 `json:"max_ms"`
+}
+
+// Interval returns the target's check interval, or def if it has none.
+func (t *Target) Interval(def time.Duration) time.Duration {
+	if t.IntervalMS > 0 {
+		return time.Duration(t.IntervalMS) * time.Millisecond
+	}
+	return def
+}
+
+// Timeout returns the target's check timeout, or def if it has none.
+func (t *Target) Timeout(def time.Duration) time.Duration {
+	if t.TimeoutMS > 0 {
+		return time.Duration(t.TimeoutMS) * time.Millisecond
+	}
+	return def
+}
+
+// HTTPOptions holds optional per-target settings for HTTP checks.
+// A nil value means a plain GET where any 2xx/3xx counts as up.
+type HTTPOptions struct {
+	// Request
+	Method      string            `json:"method,omitempty"` // default GET
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        string            `json:"body,omitempty"`
+	BasicAuth   *BasicAuth        `json:"basic_auth,omitempty"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+	// AcceptStatus lists the status codes counted as up (default: any 2xx/3xx).
+	AcceptStatus []int `json:"accept_status,omitempty"`
+
+	// Body assertions; all that are set must hold.
+	BodyContains    string `json:"body_contains,omitempty"`
+	BodyNotContains string `json:"body_not_contains,omitempty"`
+	BodyRegex       string `json:"body_regex,omitempty"`
+	// JSON assertions on the response, e.g. `$.status == "ok"` or `$.checks.db.healthy == true`.
+	JSON []string `json:"json,omitempty"`
+	// MaxBodyBytes caps how much of the body is read for assertions (0 = checker default).
+	MaxBodyBytes int64 `json:"max_body_bytes,omitempty"`
+}
+
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+const redacted = "***"
+
+// safeHeaders are the request headers whose values are shown over the API.
+// Any other header may carry a credential (X-Auth-Token, X-Vault-Token, ...),
+// so its value is masked.
+var safeHeaders = map[string]bool{
+	"accept":          true,
+	"accept-encoding": true,
+	"accept-language": true,
+	"cache-control":   true,
+	"content-type":    true,
+	"host":            true,
+	"origin":          true,
+	"pragma":          true,
+	"referer":         true,
+	"user-agent":      true,
+}
+
+// Redacted returns a copy safe to show over the API: credentials and the
+// values of all but well-known harmless headers are masked.
+func (o *HTTPOptions) Redacted() *HTTPOptions {
+	if o == nil {
+		return nil
+	}
+	cp := *o
+	if cp.BasicAuth != nil {
+		cp.BasicAuth = &BasicAuth{Username: cp.BasicAuth.Username, Password: redacted}
+	}
+	if cp.BearerToken != "" {
+		cp.BearerToken = redacted
+	}
+	if len(cp.Headers) > 0 {
+		cp.Headers = make(map[string]string, len(o.Headers))
+		for k, v := range o.Headers {
+			if !safeHeaders[strings.ToLower(k)] {
+				v = redacted
+			}
+			cp.Headers[k] = v
+		}
+	}
+	return &cp
+}
+
+// Unredact puts back the stored secrets wherever o (typically options read
+// over the API, edited and sent back) still holds the redacted placeholder.
+func (o *HTTPOptions) Unredact(stored *HTTPOptions) {
+	if o == nil || stored == nil {
+		return
+	}
+	if o.BasicAuth != nil && o.BasicAuth.Password == redacted && stored.BasicAuth != nil {
+		o.BasicAuth.Password = stored.BasicAuth.Password
+	}
+	if o.BearerToken == redacted {
+		o.BearerToken = stored.BearerToken
+	}
+	for k, v := range o.Headers {
+		if sv, ok := stored.Headers[k]; ok && v == redacted {
+			o.Headers[k] = sv
+		}
+	}
+}
+
+// HasRedacted reports whether o holds the redacted placeholder as a secret
+// or header value, i.e. one with no stored value to restore.
+func (o *HTTPOptions) HasRedacted() bool {
+	if o == nil {
+		return false
+	}
+	if (o.BasicAuth != nil && o.BasicAuth.Password == redacted) || o.BearerToken == redacted {
+		return true
+	}
+	for _, v := range o.Headers {
+		if v == redacted {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a shallow copy of t with its HTTP options redacted.
+func (t *Target) Redacted() *Target {
+	cp := *t
+	cp.HTTP = t.HTTP.Redacted()
+	return &cp
 }
 
 type CheckResult struct {
@@ -17,4 +178,17 @@ type CheckResult struct {
 	LatencyMS  float64   `json:"latency_ms"`
 	Reason     string    `json:"reason,omitempty"`
 	CheckedAt  time.Time `json:"checked_at"`
+	Degraded   bool      `json:"degraded,omitempty"`
+	Cert       *CertInfo `json:"cert,omitempty"`
+}
+
+// CertInfo describes the leaf certificate seen during a TLS handshake.
+// Expiring is set by the checker when NotAfter falls inside its warning window.
+type CertInfo struct {
+	NotAfter   time.Time `json:"not_after"`
+	Issuer     string    `json:"issuer"`
+	SANs       []string  `json:"sans,omitempty"`
+	ChainValid bool      `json:"chain_valid"`
+	ChainError string    `json:"chain_error,omitempty"`
+	Expiring   bool      `json:"expiring,omitempty"`
 }
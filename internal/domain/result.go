@@ -10,4 +10,25 @@ type Result struct {
 	LatencyMS  *float64  `json:"latency_ms"`  // pointer to allow nil
 	Reason     string    `json:"reason"`
 	CheckedAt  time.Time `json:"checked_at"`
+	Degraded   bool      `json:"degraded"`
+}
+
+// UptimeReport summarizes availability for one target over [From, To).
+// Durations are in seconds; latency percentiles cover successful checks only
+// and are nil when there were none.
+type UptimeReport struct {
+	TargetID    TargetID  `json:"target_id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Checks      int       `json:"checks"`
+	UptimePct   *float64  `json:"uptime_pct"` // nil when nothing is known about the window
+	DowntimeSec float64   `json:"downtime_sec"`
+	// MonitoredSec is the part of the window with a known state, which
+	// UptimePct is relative to.
+	MonitoredSec float64  `json:"monitored_sec"`
+	Outages      int      `json:"outages"`
+	MTTRSec      *float64 `json:"mttr_sec"` // mean time to recovery of outages that ended in the window
+	LatencyP50   *float64 `json:"latency_p50_ms"`
+	LatencyP95   *float64 `json:"latency_p95_ms"`
+	LatencyP99   *float64 `json:"latency_p99_ms"`
 }
@@ -0,0 +1,152 @@
+// Package events is an in-process pub/sub for check results. Producers (the
+// scheduler and the add-target handler) publish each result; subscribers such
+// as the SSE stream receive results and derived state changes as they happen.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+// Event types.
+const (
+	TypeResult = "result"
+	TypeState  = "state"
+)
+
+// Target states carried by state events.
+const (
+	StateUp       = "up"
+	StateDegraded = "degraded"
+	StateDown     = "down"
+)
+
+// Event is one published item. Result is set for result events; From/To for
+// state events.
+type Event struct {
+	ID       uint64              `json:"id"`
+	Type     string              `json:"type"`
+	TargetID domain.TargetID     `json:"target_id"`
+	At       time.Time           `json:"at"`
+	Result   *domain.CheckResult `json:"result,omitempty"`
+	From     string              `json:"from,omitempty"`
+	To       string              `json:"to,omitempty"`
+}
+
+// Bus fans events out to subscribers. Publishing never blocks: a subscriber
+// whose buffer is full misses events (and can resync from the REST API).
+// All methods are safe on a nil *Bus.
+type Bus struct {
+	mu     sync.Mutex
+	subs   map[chan Event]struct{}
+	states map[domain.TargetID]string
+	seq    uint64
+	closed bool
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subs:   make(map[chan Event]struct{}),
+		states: make(map[domain.TargetID]string),
+	}
+}
+
+// PublishResult publishes a result event, followed by a state event if the
+// target's state differs from its previous published result. The first
+// result seen for a target only records its state.
+func (b *Bus) PublishResult(cr *domain.CheckResult) {
+	if b == nil || cr == nil {
+		return
+	}
+	cp := *cr
+	to := StateOf(&cp)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from, known := b.states[cp.TargetID]
+	b.states[cp.TargetID] = to
+
+	b.send(Event{Type: TypeResult, TargetID: cp.TargetID, At: cp.CheckedAt, Result: &cp})
+	if known && from != to {
+		b.send(Event{Type: TypeState, TargetID: cp.TargetID, At: cp.CheckedAt, From: from, To: to})
+	}
+}
+
+// Forget drops the remembered state of a deleted target.
+func (b *Bus) Forget(id domain.TargetID) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	delete(b.states, id)
+	b.mu.Unlock()
+}
+
+// Subscribe registers a subscriber with the given buffer size. Call the
+// returned function to unsubscribe; it closes the channel. After Close the
+// channel is returned already closed.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	if b == nil {
+		return ch, func() {}
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		_, ok := b.subs[ch]
+		delete(b.subs, ch)
+		b.mu.Unlock()
+		if ok {
+			close(ch)
+		}
+	}
+}
+
+// Close ends every subscription, so long-lived readers such as SSE streams
+// return and the HTTP server can shut down without waiting for them.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// send must be called with b.mu held.
+func (b *Bus) send(ev Event) {
+	b.seq++
+	ev.ID = b.seq
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default: // slow subscriber; drop
+		}
+	}
+}
+
+// StateOf maps a result to up, degraded or down.
+func StateOf(cr *domain.CheckResult) string {
+	switch {
+	case !cr.Up:
+		return StateDown
+	case cr.Degraded:
+		return StateDegraded
+	default:
+		return StateUp
+	}
+}
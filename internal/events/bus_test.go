@@ -0,0 +1,101 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+func recv(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return Event{}
+	}
+}
+
+func TestBus_ResultsAndStateChanges(t *testing.T) {
+	b := NewBus()
+	ch, cancel := b.Subscribe(16)
+	defer cancel()
+
+	b.PublishResult(&domain.CheckResult{TargetID: "a", Up: true})
+	if ev := recv(t, ch); ev.Type != TypeResult || ev.Result == nil || !ev.Result.Up {
+		t.Fatalf("first: %+v", ev)
+	}
+
+	b.PublishResult(&domain.CheckResult{TargetID: "a", Up: true})
+	b.PublishResult(&domain.CheckResult{TargetID: "a", Up: false})
+	if ev := recv(t, ch); ev.Type != TypeResult {
+		t.Fatalf("want result, got %+v", ev)
+	}
+	if ev := recv(t, ch); ev.Type != TypeResult || ev.Result.Up {
+		t.Fatalf("want down result, got %+v", ev)
+	}
+	ev := recv(t, ch)
+	if ev.Type != TypeState || ev.From != StateUp || ev.To != StateDown {
+		t.Fatalf("want up->down state event, got %+v", ev)
+	}
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected extra event %+v", ev)
+	default:
+	}
+}
+
+func TestBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	b := NewBus()
+	_, cancel := b.Subscribe(1)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			b.PublishResult(&domain.CheckResult{TargetID: "a", Up: i%2 == 0})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a full subscriber")
+	}
+}
+
+func TestBus_UnsubscribeClosesAndNilIsNoop(t *testing.T) {
+	b := NewBus()
+	ch, cancel := b.Subscribe(1)
+	cancel()
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Fatal("channel should be closed")
+	}
+	b.PublishResult(&domain.CheckResult{TargetID: "a"})
+
+	var nb *Bus
+	nb.PublishResult(&domain.CheckResult{TargetID: "a"})
+	nb.Forget("a")
+	_, stop := nb.Subscribe(1)
+	stop()
+	nb.Close()
+}
+
+func TestBus_CloseEndsSubscriptions(t *testing.T) {
+	b := NewBus()
+	ch, cancel := b.Subscribe(1)
+	b.Close()
+	if _, ok := <-ch; ok {
+		t.Fatal("channel should be closed")
+	}
+	cancel() // no double close
+	b.PublishResult(&domain.CheckResult{TargetID: "a"})
+
+	late, _ := b.Subscribe(1)
+	if _, ok := <-late; ok {
+		t.Fatal("subscribing after Close should return a closed channel")
+	}
+}
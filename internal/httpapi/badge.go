@@ -0,0 +1,227 @@
+package httpapi
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+// Badge colours, as used by shields.io.
+const (
+	badgeGreen  = "#4c1"
+	badgeYellow = "#dfb317"
+	badgeOrange = "#fe7d37"
+	badgeRed    = "#e05d44"
+	badgeGrey   = "#9f9f9f"
+)
+
+// How long clients and proxies may cache badges. Uptime moves slowly, so it
+// can be cached longer than the current state.
+const (
+	statusBadgeMaxAge = 60
+	uptimeBadgeMaxAge = 300
+)
+
+// maxBadgeWindow bounds ?window= on the public uptime badge, which would
+// otherwise let anyone scan a target's whole history on every request.
+const maxBadgeWindow = 90 * 24 * time.Hour
+
+var badgeTmpl = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Value}}">` +
+	`<title>{{.Label}}: {{.Value}}</title>` +
+	`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` +
+	`<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>` +
+	`<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="20" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.ValueWidth}}" height="20" fill="{{.Color}}"/><rect width="{{.Width}}" height="20" fill="url(#s)"/></g>` +
+	`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+	`<text x="{{.LabelX}}" y="15" fill="#010101" fill-opacity=".3">{{.Label}}</text><text x="{{.LabelX}}" y="14">{{.Label}}</text>` +
+	`<text x="{{.ValueX}}" y="15" fill="#010101" fill-opacity=".3">{{.Value}}</text><text x="{{.ValueX}}" y="14">{{.Value}}</text>` +
+	`</g></svg>`))
+
+type badge struct {
+	Label, Value, Color    string
+	LabelWidth, ValueWidth int
+}
+
+func (b badge) Width() int  { return b.LabelWidth + b.ValueWidth }
+func (b badge) LabelX() int { return b.LabelWidth / 2 }
+func (b badge) ValueX() int { return b.LabelWidth + b.ValueWidth/2 }
+
+func newBadge(label, value, color string) badge {
+	return badge{Label: label, Value: value, Color: color, LabelWidth: textWidth(label), ValueWidth: textWidth(value)}
+}
+
+// textWidth approximates the rendered width of s in 11px Verdana plus padding.
+func textWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("il.:|!' ", r):
+			w += 4
+		case strings.ContainsRune("mwMW%", r):
+			w += 10
+		case r >= 'A' && r <= 'Z':
+			w += 8
+		default:
+			w += 7
+		}
+	}
+	return w + 10
+}
+
+func (s *Server) mountBadges(r chi.Router) {
+	r.Get("/badge/{id}/status.svg", s.handleStatusBadge)
+	r.Get("/badge/{id}/uptime.svg", s.handleUptimeBadge)
+}
+
+// handleStatusBadge serves GET /badge/{id}/status.svg from the latest result.
+func (s *Server) handleStatusBadge(w http.ResponseWriter, r *http.Request) {
+	label := badgeLabel(r, "status")
+	t, ok := s.badgeTarget(w, r, label)
+	if !ok {
+		return
+	}
+	b := newBadge(label, "unknown", badgeGrey)
+	if t.Paused {
+		b = newBadge(label, "paused", badgeGrey)
+	} else {
+		page, err := s.Results.History(r.Context(), repo.HistoryQuery{TargetID: string(t.ID), Limit: 1})
+		if err != nil {
+			s.badgeError(w, r, label, err)
+			return
+		}
+		if len(page.Results) > 0 {
+			switch res := page.Results[0]; {
+			case !res.Up:
+				b = newBadge(label, "down", badgeRed)
+			case res.Degraded:
+				b = newBadge(label, "degraded", badgeYellow)
+			default:
+				b = newBadge(label, "up", badgeGreen)
+			}
+		}
+	}
+	s.writeBadge(w, r, http.StatusOK, b, statusBadgeMaxAge)
+}
+
+// handleUptimeBadge serves GET /badge/{id}/uptime.svg?window=30d.
+func (s *Server) handleUptimeBadge(w http.ResponseWriter, r *http.Request) {
+	win := r.URL.Query().Get("window")
+	if win == "" {
+		win = "30d"
+	}
+	label := badgeLabel(r, "uptime "+win)
+	d, err := parseWindow(win)
+	if err != nil {
+		s.writeBadge(w, r, http.StatusBadRequest, newBadge(label, "invalid window", badgeGrey), 0)
+		return
+	}
+	if d > maxBadgeWindow {
+		s.writeBadge(w, r, http.StatusBadRequest, newBadge(label, "window over 90d", badgeGrey), 0)
+		return
+	}
+	t, ok := s.badgeTarget(w, r, label)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	rep, err := s.Results.Uptime(r.Context(), string(t.ID), now.Add(-d), now)
+	if err != nil {
+		s.badgeError(w, r, label, err)
+		return
+	}
+	b := newBadge(label, "no data", badgeGrey)
+	if p := rep.UptimePct; p != nil {
+		b = newBadge(label, formatPct(*p), uptimeColor(*p))
+	}
+	s.writeBadge(w, r, http.StatusOK, b, uptimeBadgeMaxAge)
+}
+
+// badgeLabel returns ?label= or def; READMEs often show the service name.
+func badgeLabel(r *http.Request, def string) string {
+	if l := strings.TrimSpace(r.URL.Query().Get("label")); l != "" && len(l) <= maxGroupLen {
+		return l
+	}
+	return def
+}
+
+// badgeTarget loads the {id} target, answering with a "not found" badge
+// rather than JSON so embedded images still render something. Public badges
+// are only served for targets opted in to the status page; others look
+// unknown, so their IDs can't be probed.
+func (s *Server) badgeTarget(w http.ResponseWriter, r *http.Request, label string) (*domain.Target, bool) {
+	t, err := s.Targets.Find(r.Context(), domain.TargetID(chi.URLParam(r, "id")))
+	if err != nil {
+		s.badgeError(w, r, label, err)
+		return nil, false
+	}
+	if t == nil || (s.PublicBadges && !t.StatusPage) {
+		s.writeBadge(w, r, http.StatusNotFound, newBadge(label, "not found", badgeGrey), 0)
+		return nil, false
+	}
+	return t, true
+}
+
+func (s *Server) badgeError(w http.ResponseWriter, r *http.Request, label string, err error) {
+	s.Metrics.StoreError("badge")
+	s.Logger.Warn("badge_error", zap.Error(err))
+	s.writeBadge(w, r, http.StatusInternalServerError, newBadge(label, "error", badgeGrey), 0)
+}
+
+// writeBadge renders b with an ETag; maxAge 0 disables caching.
+func (s *Server) writeBadge(w http.ResponseWriter, r *http.Request, code int, b badge, maxAge int) {
+	var buf bytes.Buffer
+	if err := badgeTmpl.Execute(&buf, b); err != nil {
+		s.Logger.Warn("badge_render_error", zap.Error(err))
+		http.Error(w, "badge unavailable", http.StatusInternalServerError)
+		return
+	}
+	sum := sha1.Sum(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	if maxAge > 0 {
+		scope := "private"
+		if s.PublicBadges {
+			scope = "public"
+		}
+		w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, maxAge))
+		w.Header().Set("ETag", etag)
+		if code == http.StatusOK && r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.WriteHeader(code)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func uptimeColor(p float64) string {
+	switch {
+	case p >= 99.9:
+		return badgeGreen
+	case p >= 99:
+		return badgeYellow
+	case p >= 95:
+		return badgeOrange
+	default:
+		return badgeRed
+	}
+}
+
+// formatPct trims needless precision: 100%, 99.95%, 87.3%.
+func formatPct(p float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", p), "0"), ".")
+	return s + "%"
+}
@@ -62,6 +62,12 @@ func minFloat(a, b float64) float64 {
 // RateLimit returns a middleware that rate-limits by remote IP.
 // Example: RateLimit(120, 60) => 120 req/min with burst 60
 func RateLimit(reqPerMin int, burst int) func(http.Handler) http.Handler {
+	return RateLimitObserved(reqPerMin, burst, nil)
+}
+
+// RateLimitObserved is RateLimit with a callback run for every rejected
+// request (e.g. to count rejections). onReject may be nil.
+func RateLimitObserved(reqPerMin int, burst int, onReject func()) func(http.Handler) http.Handler {
 	if reqPerMin <= 0 {
 		// disabled
 		return func(next http.Handler) http.Handler { return next }
@@ -73,6 +79,9 @@ func RateLimit(reqPerMin int, burst int) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			key := clientIP(r)
 			if !l.allow(key) {
+				if onReject != nil {
+					onReject()
+				}
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusTooManyRequests)
 				_, _ = w.Write([]byte(`{"error":"rate limit exceeded"}`))
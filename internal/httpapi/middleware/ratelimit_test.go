@@ -34,3 +34,19 @@ func TestRateLimit_AllowsThenBlocks(t *testing.T) {
 		t.Fatalf("want 200 after refill got %d", rr2.Code)
 	}
 }
+
+func TestRateLimitObserved_CallsOnReject(t *testing.T) {
+	rejected := 0
+	h := RateLimitObserved(60, 1, func() { rejected++ })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "5.6.7.8:1234"
+
+	for i := 0; i < 3; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if rejected != 2 {
+		t.Fatalf("want 2 rejections got %d", rejected)
+	}
+}
@@ -3,8 +3,11 @@ package httpapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,17 +16,31 @@ import (
 	"go.uber.org/zap"
 
 	apimw "github.com/hamed0406/uptimechecker/internal/httpapi/middleware"
+	"github.com/hamed0406/uptimechecker/internal/metrics"
 
 	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/events"
 	"github.com/hamed0406/uptimechecker/internal/probe"
 	"github.com/hamed0406/uptimechecker/internal/repo"
 )
 
 type Server struct {
-	Logger  *zap.Logger
-	Targets repo.TargetStore
-	Results repo.ResultStore
-	Checker probe.Checker
+	Logger     *zap.Logger
+	Targets    repo.TargetStore
+	Results    repo.ResultStore
+	Checker    probe.Checker
+	Incidents  repo.IncidentStore // optional; enables the incident routes
+	Metrics    *metrics.Metrics   // optional; enables GET /metrics
+	StatusPage *StatusPage        // optional; enables GET /status
+	Events     *events.Bus        // optional; enables GET /api/stream
+	// Notifications is optional; enables GET /api/notifications.
+	Notifications repo.OutboxStore
+	// PublicBadges serves /badge/* without an API key so they can be
+	// embedded in READMEs and wikis.
+	PublicBadges bool
+	// CheckTimeout bounds the probe run when a target is added, for targets
+	// without their own timeout (default 10s).
+	CheckTimeout time.Duration
 }
 
 func NewServer(l *zap.Logger, ts repo.TargetStore, rs repo.ResultStore, c probe.Checker) *Server {
@@ -43,7 +60,7 @@ func (s *Server) Router(
 	if len(allowedOrigins) > 0 {
 		r.Use(cors.Handler(cors.Options{
 			AllowedOrigins:   allowedOrigins,
-			AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
+			AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
 			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key"},
 			ExposedHeaders:   []string{"Link"},
 			AllowCredentials: false,
@@ -54,9 +71,25 @@ func (s *Server) Router(
 	}
 
 	// Public/read routes
+	publicStatus := s.StatusPage != nil && s.StatusPage.Public
+
+	// Unauthenticated status page and badges: rate limited like the public
+	// API, but outside its API-key check.
+	if publicStatus || s.PublicBadges {
+		r.Group(func(st chi.Router) {
+			st.Use(apimw.RateLimitObserved(publicRPM, publicBurst, s.Metrics.RateLimited("status")))
+			if publicStatus {
+				st.Get("/status", s.handleStatusPage)
+			}
+			if s.PublicBadges {
+				s.mountBadges(st)
+			}
+		})
+	}
+
 	r.Group(func(pub chi.Router) {
 		pub.Use(apimw.RequireAny(keys))
-		pub.Use(apimw.RateLimit(publicRPM, publicBurst)) // env-driven limits
+		pub.Use(apimw.RateLimitObserved(publicRPM, publicBurst, s.Metrics.RateLimited("public"))) // env-driven limits
 
 		pub.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
@@ -64,58 +97,126 @@ func (s *Server) Router(
 			_, _ = w.Write([]byte("ok"))
 		})
 
+		if s.Metrics != nil {
+			pub.Method(http.MethodGet, "/metrics", s.Metrics.Handler())
+		}
+		if s.StatusPage != nil && !publicStatus {
+			pub.Get("/status", s.handleStatusPage)
+		}
+		if !s.PublicBadges {
+			s.mountBadges(pub)
+		}
+		if s.Events != nil {
+			pub.Get("/api/stream", s.handleStream)
+		}
 		pub.Get("/api/targets", s.handleListTargets)
 		pub.Get("/api/results/latest", s.handleLatest)
+		pub.Get("/api/targets/{id}/results", s.handleHistory)
+		pub.Get("/api/targets/{id}/uptime", s.handleUptime)
+		if s.Incidents != nil {
+			pub.Get("/api/incidents", s.handleIncidents)
+			pub.Get("/api/incidents/{incidentID}", s.handleIncident)
+			pub.Get("/api/targets/{id}/incidents", s.handleIncidents)
+		}
 	})
 
 	// Admin/write routes
 	r.Group(func(adm chi.Router) {
 		adm.Use(apimw.RequireAdmin(keys))
-		adm.Use(apimw.RateLimit(adminRPM, adminBurst))
+		adm.Use(apimw.RateLimitObserved(adminRPM, adminBurst, s.Metrics.RateLimited("admin")))
 		adm.Post("/api/targets", s.handleAddTarget)
+		adm.Patch("/api/targets/{id}", s.handleUpdateTarget)
+		adm.Delete("/api/targets/{id}", s.handleDeleteTarget)
+		if s.Incidents != nil {
+			adm.Post("/api/incidents/{incidentID}/ack", s.handleAckIncident)
+		}
+		// Admin-only: delivery errors can reveal notifier settings.
+		if s.Notifications != nil {
+			adm.Get("/api/notifications", s.handleNotifications)
+		}
+		adm.Post("/api/targets/{id}/pause", s.handlePause(true))
+		adm.Post("/api/targets/{id}/resume", s.handlePause(false))
 	})
 
 	return r
 }
 
 type addPayload struct {
-	URL string `json:"url"`
+	URL        string                   `json:"url"`
+	HTTP       *domain.HTTPOptions      `json:"http,omitempty"`
+	IntervalMS int                      `json:"interval_ms,omitempty"`
+	TimeoutMS  int                      `json:"timeout_ms,omitempty"`
+	Group      string                   `json:"group,omitempty"`
+	StatusPage bool                     `json:"status_page,omitempty"`
+	Labels     map[string]string        `json:"labels,omitempty"`
+	DownAfter  int                      `json:"down_after,omitempty"`
+	UpAfter    int                      `json:"up_after,omitempty"`
+	Latency    *domain.LatencyThreshold `json:"latency,omitempty"`
 }
 
+// maxGroupLen bounds status page section names.
+const maxGroupLen = 64
+
+// Label limits; keys look like Prometheus label names.
+const (
+	maxLabels      = 32
+	maxLabelValLen = 128
+)
+
+var labelKeyRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]{0,62}$`)
+
+// maxStreak bounds down_after/up_after (each alert scan reads that many results).
+const maxStreak = 100
+
+// minIntervalMS keeps a single target from hammering its endpoint.
+const minIntervalMS = 1000
+
 func (s *Server) handleAddTarget(w http.ResponseWriter, r *http.Request) {
 	var p addPayload
 	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid json"})
 		return
 	}
-	raw := strings.TrimSpace(p.URL)
-	if !isValidHTTPURL(raw) {
-		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid url"})
+	t := &domain.Target{
+		URL:        strings.TrimSpace(p.URL),
+		CreatedAt:  time.Now().UTC(),
+		HTTP:       p.HTTP,
+		IntervalMS: p.IntervalMS,
+		TimeoutMS:  p.TimeoutMS,
+		Group:      strings.TrimSpace(p.Group),
+		StatusPage: p.StatusPage,
+		Labels:     trimLabels(p.Labels),
+		DownAfter:  p.DownAfter,
+		UpAfter:    p.UpAfter,
+		Latency:    latencyThreshold(p.Latency),
+	}
+	if msg := validateTarget(t); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": msg})
 		return
 	}
-	normalized := normalizeHTTPURL(raw)
+	t.URL = normalizeHTTPURL(t.URL)
+	normalized := t.URL
 
 	// Duplicate guard (store-agnostic)
-	if existing, err := s.Targets.List(r.Context()); err == nil {
-		for _, t := range existing {
-			if normalizeHTTPURL(t.URL) == normalized {
-				writeJSON(w, http.StatusConflict, map[string]any{"error": "target already exists"})
-				return
-			}
-		}
+	if s.urlTaken(r.Context(), normalized, "") {
+		writeJSON(w, http.StatusConflict, map[string]any{"error": "target already exists"})
+		return
 	}
 
 	// Save
-	t := &domain.Target{URL: normalized, CreatedAt: time.Now().UTC()}
 	if err := s.Targets.Add(r.Context(), t); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "could not add target"})
 		return
 	}
 
-	// Immediate probe (checker has its own timeout; also guard with context timeout)
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	// Immediate probe, bounded like the scheduled ones
+	def := s.CheckTimeout
+	if def <= 0 {
+		def = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), t.Timeout(def))
 	defer cancel()
-	out := s.Checker.Check(ctx, normalized)
+	out := s.Checker.Check(probe.WithHTTPOptions(ctx, t.HTTP), normalized)
 
 	cr := &domain.CheckResult{
 		TargetID:   t.ID,
@@ -124,8 +225,18 @@ func (s *Server) handleAddTarget(w http.ResponseWriter, r *http.Request) {
 		LatencyMS:  out.LatencyMS,
 		Reason:     out.Message,
 		CheckedAt:  time.Now().UTC(),
+		Degraded:   out.Degraded,
+		Cert:       out.TLS,
+	}
+	if err := s.Results.Append(ctx, cr); err != nil {
+		s.Metrics.StoreError("append_result")
+	}
+	s.Events.PublishResult(cr)
+	if s.Incidents != nil {
+		if err := s.Incidents.TrackIncident(ctx, cr); err != nil {
+			s.Metrics.StoreError("track_incident")
+		}
 	}
-	_ = s.Results.Append(ctx, cr)
 
 	s.Logger.Info("added_target",
 		zap.String("url", normalized),
@@ -136,18 +247,131 @@ func (s *Server) handleAddTarget(w http.ResponseWriter, r *http.Request) {
 	)
 
 	writeJSON(w, http.StatusOK, map[string]any{
-		"target":  t,
+		"target":  t.Redacted(),
 		"summary": cr,
 	})
 }
 
+// updatePayload is a partial update: only fields present are changed.
+type updatePayload struct {
+	URL        *string                  `json:"url"`
+	HTTP       *domain.HTTPOptions      `json:"http"`
+	IntervalMS *int                     `json:"interval_ms"`
+	TimeoutMS  *int                     `json:"timeout_ms"`
+	Group      *string                  `json:"group"`
+	StatusPage *bool                    `json:"status_page"`
+	Labels     *map[string]string       `json:"labels"` // replaces all labels; {} clears them
+	DownAfter  *int                     `json:"down_after"`
+	UpAfter    *int                     `json:"up_after"`
+	Latency    *domain.LatencyThreshold `json:"latency"` // max_ms 0 removes the threshold
+}
+
+func (s *Server) handleUpdateTarget(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.findTarget(w, r)
+	if !ok {
+		return
+	}
+	var p updatePayload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid json"})
+		return
+	}
+	oldURL := t.URL
+	if p.URL != nil {
+		t.URL = strings.TrimSpace(*p.URL)
+	}
+	if p.HTTP != nil {
+		// options read from the API come back with "***" for secrets
+		p.HTTP.Unredact(t.HTTP)
+		t.HTTP = p.HTTP
+	}
+	if p.IntervalMS != nil {
+		t.IntervalMS = *p.IntervalMS
+	}
+	if p.TimeoutMS != nil {
+		t.TimeoutMS = *p.TimeoutMS
+	}
+	if p.Group != nil {
+		t.Group = strings.TrimSpace(*p.Group)
+	}
+	if p.StatusPage != nil {
+		t.StatusPage = *p.StatusPage
+	}
+	if p.Labels != nil {
+		t.Labels = trimLabels(*p.Labels)
+	}
+	if p.DownAfter != nil {
+		t.DownAfter = *p.DownAfter
+	}
+	if p.UpAfter != nil {
+		t.UpAfter = *p.UpAfter
+	}
+	if p.Latency != nil {
+		t.Latency = latencyThreshold(p.Latency)
+	}
+	if msg := validateTarget(t); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": msg})
+		return
+	}
+	t.URL = normalizeHTTPURL(t.URL)
+	if s.urlTaken(r.Context(), t.URL, t.ID) {
+		writeJSON(w, http.StatusConflict, map[string]any{"error": "target already exists"})
+		return
+	}
+	if err := s.Targets.Update(r.Context(), t); err != nil {
+		s.writeStoreError(w, "update_target", err)
+		return
+	}
+	if t.URL != oldURL {
+		s.Metrics.ForgetTarget(string(t.ID)) // the series are labelled with the old URL
+	}
+	s.Logger.Info("updated_target", zap.String("target_id", string(t.ID)), zap.String("url", t.URL))
+	writeJSON(w, http.StatusOK, t.Redacted())
+}
+
+func (s *Server) handleDeleteTarget(w http.ResponseWriter, r *http.Request) {
+	id := domain.TargetID(chi.URLParam(r, "id"))
+	if err := s.Targets.Delete(r.Context(), id); err != nil {
+		s.writeStoreError(w, "delete_target", err)
+		return
+	}
+	s.Metrics.ForgetTarget(string(id))
+	s.Events.Forget(id)
+	s.Logger.Info("deleted_target", zap.String("target_id", string(id)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePause serves POST /api/targets/{id}/pause and /resume.
+func (s *Server) handlePause(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := domain.TargetID(chi.URLParam(r, "id"))
+		if err := s.Targets.SetPaused(r.Context(), id, paused); err != nil {
+			s.writeStoreError(w, "pause_target", err)
+			return
+		}
+		if paused {
+			s.Metrics.ForgetTarget(string(id)) // no longer checked, so no current values
+		}
+		s.Logger.Info("paused_target", zap.String("target_id", string(id)), zap.Bool("paused", paused))
+		t, ok := s.findTarget(w, r)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, t.Redacted())
+	}
+}
+
 func (s *Server) handleListTargets(w http.ResponseWriter, r *http.Request) {
 	ts, err := s.Targets.List(r.Context())
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "list error"})
 		return
 	}
-	writeJSON(w, http.StatusOK, ts)
+	out := make([]*domain.Target, 0, len(ts))
+	for _, t := range ts {
+		out = append(out, t.Redacted())
+	}
+	writeJSON(w, http.StatusOK, out)
 }
 
 func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
@@ -159,8 +383,405 @@ func (s *Server) handleLatest(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, rows)
 }
 
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+)
+
+// handleHistory serves GET /api/targets/{id}/results?from=&to=&limit=&cursor=.
+// from/to are RFC3339; results are newest first and next_cursor is set while
+// more rows remain.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	q := repo.HistoryQuery{TargetID: id, Limit: defaultHistoryLimit}
+
+	var err error
+	if q.From, err = parseTimeParam(r, "from"); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid from"})
+		return
+	}
+	if q.To, err = parseTimeParam(r, "to"); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid to"})
+		return
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxHistoryLimit {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "limit must be 1-1000"})
+			return
+		}
+		q.Limit = n
+	}
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		if q.After, err = repo.DecodeCursor(v); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid cursor"})
+			return
+		}
+	}
+
+	if !s.targetExists(r.Context(), id) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "target not found"})
+		return
+	}
+
+	page, err := s.Results.History(r.Context(), q)
+	if err != nil {
+		s.Logger.Warn("history_error", zap.String("target_id", id), zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "history error"})
+		return
+	}
+	resp := map[string]any{"results": page.Results}
+	if page.Next != nil {
+		resp["next_cursor"] = page.Next.Encode()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleUptime serves GET /api/targets/{id}/uptime?window=24h|7d|30d, or a
+// custom range with from/to (RFC3339). The default window is 24h.
+func (s *Server) handleUptime(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	from, to, err := reportRange(r, time.Now().UTC())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	if !s.targetExists(r.Context(), id) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "target not found"})
+		return
+	}
+	rep, err := s.Results.Uptime(r.Context(), id, from, to)
+	if err != nil {
+		s.Logger.Warn("uptime_error", zap.String("target_id", id), zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "uptime error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, rep)
+}
+
+// handleIncidents serves GET /api/incidents and /api/targets/{id}/incidents.
+// ?status=open limits to ongoing incidents; ?limit caps the count (default 100).
+func (s *Server) handleIncidents(w http.ResponseWriter, r *http.Request) {
+	q := repo.IncidentQuery{TargetID: chi.URLParam(r, "id"), Limit: defaultHistoryLimit}
+	switch r.URL.Query().Get("status") {
+	case "", "all":
+	case "open":
+		q.OpenOnly = true
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "status must be open or all"})
+		return
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxHistoryLimit {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "limit must be 1-1000"})
+			return
+		}
+		q.Limit = n
+	}
+	if q.TargetID != "" && !s.targetExists(r.Context(), q.TargetID) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "target not found"})
+		return
+	}
+	out, err := s.Incidents.Incidents(r.Context(), q)
+	if err != nil {
+		s.Logger.Warn("incidents_error", zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "incidents error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleIncident(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "incidentID"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid incident id"})
+		return
+	}
+	inc, err := s.Incidents.Incident(r.Context(), id)
+	if err != nil {
+		s.Logger.Warn("incident_error", zap.Int64("incident_id", id), zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "incident error"})
+		return
+	}
+	if inc == nil {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "incident not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, inc)
+}
+
+// ackPayload is the optional body of POST /api/incidents/{id}/ack.
+type ackPayload struct {
+	By string `json:"by"`
+}
+
+// maxAckByLen bounds the acknowledger's name.
+const maxAckByLen = 64
+
+// handleAckIncident acknowledges an open incident, which stops escalation.
+// Acknowledging twice keeps the first acknowledgement.
+func (s *Server) handleAckIncident(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "incidentID"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid incident id"})
+		return
+	}
+	var p ackPayload
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid json"})
+			return
+		}
+	}
+	p.By = strings.TrimSpace(p.By)
+	if len(p.By) > maxAckByLen {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "by must be at most 64 characters"})
+		return
+	}
+	if p.By == "" {
+		p.By = "api"
+	}
+
+	inc, err := s.Incidents.Incident(r.Context(), id)
+	if err != nil {
+		s.Metrics.StoreError("incident")
+		s.Logger.Warn("incident_error", zap.Int64("incident_id", id), zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "incident error"})
+		return
+	}
+	if inc == nil {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "incident not found"})
+		return
+	}
+	if !inc.Open() {
+		writeJSON(w, http.StatusConflict, map[string]any{"error": "incident is already resolved"})
+		return
+	}
+	inc, err = s.Incidents.AckIncident(r.Context(), id, p.By, time.Now().UTC())
+	if errors.Is(err, repo.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "incident not found"})
+		return
+	}
+	if err != nil {
+		s.Metrics.StoreError("ack_incident")
+		s.Logger.Warn("ack_incident_error", zap.Int64("incident_id", id), zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "incident error"})
+		return
+	}
+	s.Logger.Info("acked_incident", zap.Int64("incident_id", id), zap.String("by", inc.AckedBy))
+	writeJSON(w, http.StatusOK, inc)
+}
+
+// handleNotifications serves the outbox with each notification's delivery
+// log, newest first. ?status (pending, sent, dead) and ?target filter it;
+// ?limit caps the count (default 100).
+func (s *Server) handleNotifications(w http.ResponseWriter, r *http.Request) {
+	q := repo.NotificationQuery{
+		Status:   r.URL.Query().Get("status"),
+		TargetID: r.URL.Query().Get("target"),
+		Limit:    defaultHistoryLimit,
+	}
+	switch q.Status {
+	case "", repo.NotificationPending, repo.NotificationSent, repo.NotificationDead:
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "status must be pending, sent or dead"})
+		return
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxHistoryLimit {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "limit must be 1-1000"})
+			return
+		}
+		q.Limit = n
+	}
+	out, err := s.Notifications.Notifications(r.Context(), q)
+	if err != nil {
+		s.Metrics.StoreError("notifications")
+		s.Logger.Warn("notifications_error", zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "notifications error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
 // --- helpers ---
 
+// reportRange resolves ?window= (e.g. 24h, 7d, 30d) or ?from=&to= into a
+// [from, to) range ending at now by default.
+func reportRange(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	from, err := parseTimeParam(r, "from")
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid from")
+	}
+	to, err := parseTimeParam(r, "to")
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid to")
+	}
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		win := r.URL.Query().Get("window")
+		if win == "" {
+			win = "24h"
+		}
+		d, err := parseWindow(win)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid window")
+		}
+		from = to.Add(-d)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+// maxWindowDays keeps day windows well inside time.Duration's ~292 years.
+const maxWindowDays = 36500
+
+// parseWindow accepts Go durations plus a "d" suffix for days (e.g. "7d").
+func parseWindow(s string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil || days < 1 || days > maxWindowDays {
+			return 0, errors.New("bad window")
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, errors.New("bad window")
+	}
+	return d, nil
+}
+
+func (s *Server) targetExists(ctx context.Context, id string) bool {
+	t, err := s.Targets.Find(ctx, domain.TargetID(id))
+	return err == nil && t != nil
+}
+
+// findTarget loads the {id} target, writing a 404/500 and returning false if
+// it can't.
+func (s *Server) findTarget(w http.ResponseWriter, r *http.Request) (*domain.Target, bool) {
+	id := domain.TargetID(chi.URLParam(r, "id"))
+	t, err := s.Targets.Find(r.Context(), id)
+	if err != nil {
+		s.writeStoreError(w, "find_target", err)
+		return nil, false
+	}
+	if t == nil {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "target not found"})
+		return nil, false
+	}
+	return t, true
+}
+
+// urlTaken reports whether another target (not except) already uses url.
+func (s *Server) urlTaken(ctx context.Context, url string, except domain.TargetID) bool {
+	existing, err := s.Targets.List(ctx)
+	if err != nil {
+		return false
+	}
+	for _, t := range existing {
+		if t.ID != except && normalizeHTTPURL(t.URL) == url {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) writeStoreError(w http.ResponseWriter, op string, err error) {
+	if errors.Is(err, repo.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "target not found"})
+		return
+	}
+	s.Metrics.StoreError(op)
+	s.Logger.Warn(op+"_error", zap.Error(err))
+	writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "store error"})
+}
+
+// validateTarget checks user-supplied target fields and returns a message
+// for the first problem, or "".
+func validateTarget(t *domain.Target) string {
+	if !isValidHTTPURL(t.URL) && !isValidTCPURL(t.URL) {
+		return "invalid url"
+	}
+	if err := probe.ValidateHTTPOptions(t.HTTP); err != nil {
+		return "invalid http options: " + err.Error()
+	}
+	if t.HTTP.HasRedacted() {
+		return `invalid http options: "***" is the redacted placeholder, not a value`
+	}
+	if t.IntervalMS != 0 && t.IntervalMS < minIntervalMS {
+		return "interval_ms must be >= 1000"
+	}
+	if t.TimeoutMS < 0 || (t.IntervalMS > 0 && t.TimeoutMS > t.IntervalMS) {
+		return "timeout_ms must be >= 0 and not exceed interval_ms"
+	}
+	if len(t.Group) > maxGroupLen {
+		return "group must be at most 64 characters"
+	}
+	if msg := validateLabels(t.Labels); msg != "" {
+		return msg
+	}
+	if t.DownAfter < 0 || t.DownAfter > maxStreak || t.UpAfter < 0 || t.UpAfter > maxStreak {
+		return "down_after and up_after must be between 0 and 100"
+	}
+	if l := t.Latency; l != nil {
+		if l.Percentile <= 0 || l.Percentile > 100 || l.Samples < 1 || l.Samples > maxStreak || l.MaxMS < 0 {
+			return "latency needs percentile in (0,100], samples in [1,100] and max_ms > 0"
+		}
+	}
+	return ""
+}
+
+func validateLabels(l map[string]string) string {
+	if len(l) > maxLabels {
+		return "at most 32 labels are allowed"
+	}
+	for k, v := range l {
+		if !labelKeyRE.MatchString(k) {
+			return "invalid label name " + strconv.Quote(k)
+		}
+		if v == "" || len(v) > maxLabelValLen {
+			return "label " + k + " must have a value of 1-128 characters"
+		}
+	}
+	return ""
+}
+
+// trimLabels trims label values and maps no labels to nil.
+func trimLabels(l map[string]string) map[string]string {
+	if len(l) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(l))
+	for k, v := range l {
+		out[k] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+// latencyThreshold treats a threshold without max_ms as "none".
+func latencyThreshold(l *domain.LatencyThreshold) *domain.LatencyThreshold {
+	if l == nil || l.MaxMS == 0 {
+		return nil
+	}
+	return l
+}
+
+// parseTimeParam reads an optional RFC3339 query parameter; missing => zero time.
+func parseTimeParam(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
 func isValidHTTPURL(raw string) bool {
 	u, err := url.Parse(strings.TrimSpace(raw))
 	if err != nil {
@@ -172,6 +793,18 @@ func isValidHTTPURL(raw string) bool {
 	return u.Host != ""
 }
 
+// isValidTCPURL accepts tcp://host:port targets.
+func isValidTCPURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	if u.Scheme != "tcp" {
+		return false
+	}
+	return u.Hostname() != "" && u.Port() != "" && (u.Path == "" || u.Path == "/")
+}
+
 // normalizeHTTPURL canonicalizes host case, strips default ports, and trims trailing slash.
 func normalizeHTTPURL(raw string) string {
 	u, err := url.Parse(strings.TrimSpace(raw))
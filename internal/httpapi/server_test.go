@@ -20,6 +20,24 @@ func TestIsValidHTTPURL(t *testing.T) {
 	}
 }
 
+func TestIsValidTCPURL(t *testing.T) {
+	cases := []struct {
+		in   string
+		want bool
+	}{
+		{"tcp://db.internal:5432", true},
+		{"tcp://10.0.0.1:25", true},
+		{"tcp://db.internal", false},
+		{"tcp://:5432", false},
+		{"https://example.com", false},
+	}
+	for _, c := range cases {
+		if got := isValidTCPURL(c.in); got != c.want {
+			t.Fatalf("isValidTCPURL(%q)=%v want %v", c.in, got, c.want)
+		}
+	}
+}
+
 func TestNormalizeHTTPURL(t *testing.T) {
 	cases := []struct {
 		in, want string
@@ -28,6 +46,7 @@ func TestNormalizeHTTPURL(t *testing.T) {
 		{"http://example.com:80", "http://example.com"},
 		{"https://example.com:443/", "https://example.com"},
 		{"https://example.com/p/", "https://example.com/p/"},
+		{"tcp://DB.internal:5432", "tcp://db.internal:5432"},
 	}
 	for _, c := range cases {
 		if got := normalizeHTTPURL(c.in); got != c.want {
@@ -0,0 +1,265 @@
+package httpapi
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+// StatusPage configures the HTML status page served at GET /status.
+type StatusPage struct {
+	Title string
+	// Public serves the page without an API key, listing only targets that
+	// opted in with status_page. The JSON routes keep their own auth either way.
+	Public bool
+
+	mu       sync.Mutex
+	body     []byte // last rendered page
+	public   bool   // whether body was rendered for the public page
+	rendered time.Time
+}
+
+// statusDays is how many daily uptime bars each target shows.
+const statusDays = 90
+
+// statusCacheTTL is how long a rendered page is reused; it matches the
+// Cache-Control max-age so anonymous traffic can't force fresh history scans.
+const statusCacheTTL = 30 * time.Second
+
+//go:embed statuspage.html
+var statusPageHTML string
+
+var statusPageTmpl = template.Must(template.New("status").Funcs(template.FuncMap{
+	"pct": func(p *float64) string {
+		if p == nil {
+			return "no data"
+		}
+		return fmt.Sprintf("%.2f%%", *p)
+	},
+	"barClass": func(p *float64) string {
+		switch {
+		case p == nil:
+			return "none"
+		case *p >= 99.9:
+			return "ok"
+		case *p >= 99:
+			return "warn"
+		default:
+			return "down"
+		}
+	},
+	"day": func(t time.Time) string { return t.Format("2006-01-02") },
+	"ts":  func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+}).Parse(statusPageHTML))
+
+type statusView struct {
+	Title       string
+	Overall     string // operational, degraded, outage
+	Groups      []statusGroup
+	Incidents   []statusIncident
+	GeneratedAt time.Time
+}
+
+type statusGroup struct {
+	Name    string
+	Targets []statusTarget
+}
+
+type statusTarget struct {
+	Name      string
+	State     string // up, degraded, down, paused, unknown
+	UptimePct *float64
+	Days      []domain.UptimeReport
+}
+
+type statusIncident struct {
+	Target    string
+	StartedAt time.Time
+	Reason    string // generic, see publicReason
+}
+
+// handleStatusPage renders GET /status: targets by group with their current
+// state, daily uptime for the last statusDays days and open incidents.
+func (s *Server) handleStatusPage(w http.ResponseWriter, r *http.Request) {
+	body, err := s.statusPageBody(r, time.Now().UTC())
+	if err != nil {
+		http.Error(w, "status unavailable", http.StatusInternalServerError)
+		return
+	}
+	cache := "private, max-age=30"
+	if s.StatusPage.Public {
+		cache = "public, max-age=30"
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", cache)
+	_, _ = w.Write(body)
+}
+
+// statusPageBody returns the rendered page, re-rendering it at most once
+// per statusCacheTTL.
+func (s *Server) statusPageBody(r *http.Request, now time.Time) ([]byte, error) {
+	sp := s.StatusPage
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.body != nil && sp.public == sp.Public && now.Sub(sp.rendered) < statusCacheTTL {
+		return sp.body, nil
+	}
+	v, err := s.buildStatusView(r, now)
+	if err != nil {
+		s.Metrics.StoreError("status_page")
+		s.Logger.Warn("status_page_error", zap.Error(err))
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := statusPageTmpl.Execute(&buf, v); err != nil {
+		s.Logger.Warn("status_page_render_error", zap.Error(err))
+		return nil, err
+	}
+	sp.body, sp.public, sp.rendered = buf.Bytes(), sp.Public, now
+	return sp.body, nil
+}
+
+func (s *Server) buildStatusView(r *http.Request, now time.Time) (*statusView, error) {
+	ctx := r.Context()
+	ts, err := s.Targets.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.Results.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]repo.LatestRow, len(rows))
+	for _, row := range rows {
+		latest[row.TargetID] = row
+	}
+
+	from := now.Truncate(24*time.Hour).AddDate(0, 0, -(statusDays - 1))
+	v := &statusView{Title: s.StatusPage.Title, Overall: "operational", GeneratedAt: now}
+	names := make(map[domain.TargetID]string, len(ts))
+	byGroup := map[string][]statusTarget{}
+	var up, down int
+	for _, t := range ts {
+		if s.StatusPage.Public && !t.StatusPage {
+			continue
+		}
+		days, err := s.Results.DailyUptime(ctx, string(t.ID), from, statusDays)
+		if err != nil {
+			return nil, err
+		}
+		st := statusTarget{
+			Name:      displayName(t.URL),
+			State:     targetState(t, latest),
+			UptimePct: repo.CombineUptime(days),
+			Days:      days,
+		}
+		switch st.State {
+		case "up", "degraded":
+			up++
+		case "down":
+			down++
+		}
+		if st.State == "degraded" && v.Overall == "operational" {
+			v.Overall = "degraded"
+		}
+		names[t.ID] = st.Name
+		byGroup[t.Group] = append(byGroup[t.Group], st)
+	}
+	if down > 0 {
+		v.Overall = "outage"
+		if up > 0 {
+			v.Overall = "partial"
+		}
+	}
+
+	groups := make([]string, 0, len(byGroup))
+	for g := range byGroup {
+		groups = append(groups, g)
+	}
+	// Named groups alphabetically, the default section last.
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i] == "") != (groups[j] == "") {
+			return groups[j] == ""
+		}
+		return groups[i] < groups[j]
+	})
+	for _, g := range groups {
+		targets := byGroup[g]
+		sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+		name := g
+		if name == "" {
+			name = "Services"
+		}
+		v.Groups = append(v.Groups, statusGroup{Name: name, Targets: targets})
+	}
+
+	if s.Incidents != nil {
+		open, err := s.Incidents.Incidents(ctx, repo.IncidentQuery{OpenOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, in := range open {
+			name, ok := names[in.TargetID]
+			if !ok {
+				continue
+			}
+			row, ok := latest[string(in.TargetID)]
+			v.Incidents = append(v.Incidents, statusIncident{Target: name, StartedAt: in.StartedAt, Reason: publicReason(row, ok)})
+		}
+	}
+	return v, nil
+}
+
+func targetState(t *domain.Target, latest map[string]repo.LatestRow) string {
+	if t.Paused {
+		return "paused"
+	}
+	row, ok := latest[string(t.ID)]
+	switch {
+	case !ok:
+		return "unknown"
+	case !row.Up:
+		return "down"
+	case row.Degraded:
+		return "degraded"
+	default:
+		return "up"
+	}
+}
+
+// publicReason describes a failing target for the status page: its HTTP
+// status class, or just "down". Raw reasons (error messages, assertion
+// failures) can reveal internal hosts and response bodies, so they are left
+// to the API.
+func publicReason(row repo.LatestRow, ok bool) string {
+	switch {
+	case !ok || row.Up:
+		return ""
+	case row.HTTPStatus != nil && *row.HTTPStatus >= 400:
+		return fmt.Sprintf("HTTP %dxx", *row.HTTPStatus/100)
+	default:
+		return "down"
+	}
+}
+
+// displayName is the public label for a target: host and path, without
+// credentials or query strings that may carry secrets.
+func displayName(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	return u.Host + strings.TrimSuffix(u.Path, "/")
+}
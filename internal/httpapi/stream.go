@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+const (
+	// streamBuffer is how many events a slow client may lag behind before
+	// it starts missing them.
+	streamBuffer = 64
+	// streamHeartbeat keeps idle connections open through proxies.
+	streamHeartbeat = 15 * time.Second
+)
+
+// handleStream serves GET /api/stream: Server-Sent Events with a "result"
+// event for every check and a "state" event when a target changes between
+// up, degraded and down. ?target= limits the stream to one target.
+//
+// Clients should load /api/results/latest once and then apply events.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": "streaming unsupported"})
+		return
+	}
+	only := domain.TargetID(r.URL.Query().Get("target"))
+
+	evs, cancel := s.Events.Subscribe(streamBuffer)
+	defer cancel()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // disable nginx buffering
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "retry: 3000\n: connected\n\n")
+	flusher.Flush()
+
+	s.Logger.Debug("stream_connected", zap.String("remote", r.RemoteAddr))
+	defer s.Logger.Debug("stream_closed", zap.String("remote", r.RemoteAddr))
+
+	tick := time.NewTicker(streamHeartbeat)
+	defer tick.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-tick.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case ev, ok := <-evs:
+			if !ok {
+				return
+			}
+			if only != "" && ev.TargetID != only {
+				continue
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Package metrics exposes probe results and service internals in the
+// Prometheus text format. All methods are safe on a nil *Metrics, so
+// components can take an optional *Metrics without nil checks.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "uptime"
+
+type Metrics struct {
+	reg *prometheus.Registry
+
+	targetUp      *prometheus.GaugeVec
+	targetLatency *prometheus.GaugeVec
+	targetStatus  *prometheus.GaugeVec
+	checksTotal   *prometheus.CounterVec
+	checkDuration *prometheus.HistogramVec
+	passDuration  prometheus.Histogram
+	queueDepth    prometheus.Gauge
+	inflight      prometheus.Gauge
+	rateLimited   *prometheus.CounterVec
+	storeErrors   *prometheus.CounterVec
+	notifications *prometheus.CounterVec
+	deliveries    *prometheus.CounterVec
+}
+
+// New creates a Metrics with its own registry (plus Go runtime and process collectors).
+func New() *Metrics {
+	m := &Metrics{
+		reg: prometheus.NewRegistry(),
+		targetUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "target_up",
+			Help: "1 if the last check of the target succeeded, else 0.",
+		}, []string{"target_id", "url"}),
+		targetLatency: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "target_latency_ms",
+			Help: "Latency of the last check in milliseconds.",
+		}, []string{"target_id", "url"}),
+		targetStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "target_http_status",
+			Help: "HTTP status code of the last check (0 for transport errors and non-HTTP checks).",
+		}, []string{"target_id", "url"}),
+		checksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "checks_total",
+			Help: "Checks run, by outcome (up, degraded, down).",
+		}, []string{"target_id", "outcome"}),
+		checkDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "check_duration_seconds",
+			Help:    "Duration of individual checks.",
+			Buckets: []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"target_id"}),
+		passDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: "rechecker", Name: "pass_duration_seconds",
+			Help:    "Time the scheduler spends per wake-up refreshing targets and dispatching due checks.",
+			Buckets: prometheus.ExponentialBuckets(.0005, 4, 8),
+		}),
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "rechecker", Name: "queue_depth",
+			Help: "Targets currently scheduled.",
+		}),
+		inflight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "rechecker", Name: "inflight_checks",
+			Help: "Checks dispatched and not yet finished (running or waiting for a concurrency slot).",
+		}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "http", Name: "rate_limited_total",
+			Help: "Requests rejected by the rate limiter, by route group.",
+		}, []string{"group"}),
+		storeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "store_errors_total",
+			Help: "Persistence errors, by operation.",
+		}, []string{"op"}),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "alerter", Name: "notifications_total",
+			Help: "Notifications attempted by the alerter, by kind and result (sent, failed).",
+		}, []string{"kind", "result"}),
+		deliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "outbox", Name: "deliveries_total",
+			Help: "Delivery attempts from the notification outbox, by notifier and result (sent, failed, dead).",
+		}, []string{"notifier", "result"}),
+	}
+	m.reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.targetUp, m.targetLatency, m.targetStatus, m.checksTotal, m.checkDuration,
+		m.passDuration, m.queueDepth, m.inflight,
+		m.rateLimited, m.storeErrors, m.notifications, m.deliveries,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	if m == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.reg, promhttp.HandlerOpts{})
+}
+
+// ObserveCheck records the outcome of one check.
+func (m *Metrics) ObserveCheck(targetID, url string, up, degraded bool, status int, latencyMS float64, took time.Duration) {
+	if m == nil {
+		return
+	}
+	upVal := 0.0
+	outcome := "down"
+	if up {
+		upVal = 1
+		outcome = "up"
+		if degraded {
+			outcome = "degraded"
+		}
+	}
+	m.targetUp.WithLabelValues(targetID, url).Set(upVal)
+	m.targetLatency.WithLabelValues(targetID, url).Set(latencyMS)
+	m.targetStatus.WithLabelValues(targetID, url).Set(float64(status))
+	m.checksTotal.WithLabelValues(targetID, outcome).Inc()
+	m.checkDuration.WithLabelValues(targetID).Observe(took.Seconds())
+}
+
+// ForgetTarget drops every per-target series, e.g. after the target is
+// deleted, paused or moved to another URL.
+func (m *Metrics) ForgetTarget(targetID string) {
+	if m == nil {
+		return
+	}
+	l := prometheus.Labels{"target_id": targetID}
+	m.targetUp.DeletePartialMatch(l)
+	m.targetLatency.DeletePartialMatch(l)
+	m.targetStatus.DeletePartialMatch(l)
+	m.checksTotal.DeletePartialMatch(l)
+	m.checkDuration.DeletePartialMatch(l)
+}
+
+func (m *Metrics) ObservePass(took time.Duration) {
+	if m == nil {
+		return
+	}
+	m.passDuration.Observe(took.Seconds())
+}
+
+func (m *Metrics) SetQueueDepth(n int) {
+	if m == nil {
+		return
+	}
+	m.queueDepth.Set(float64(n))
+}
+
+// CheckStarted / CheckFinished track in-flight checks.
+func (m *Metrics) CheckStarted() {
+	if m == nil {
+		return
+	}
+	m.inflight.Inc()
+}
+
+func (m *Metrics) CheckFinished() {
+	if m == nil {
+		return
+	}
+	m.inflight.Dec()
+}
+
+// RateLimited returns a callback counting rejections for one route group,
+// suitable for middleware.RateLimitObserved.
+func (m *Metrics) RateLimited(group string) func() {
+	if m == nil {
+		return nil
+	}
+	c := m.rateLimited.WithLabelValues(group)
+	return c.Inc
+}
+
+// StoreError counts a failed repository call, labelled by operation.
+func (m *Metrics) StoreError(op string) {
+	if m == nil {
+		return
+	}
+	m.storeErrors.WithLabelValues(op).Inc()
+}
+
+// Notification counts one alerter send attempt of the given kind (down,
+// recovered, cert_expiring, ...).
+func (m *Metrics) Notification(kind string, err error) {
+	if m == nil {
+		return
+	}
+	m.notifications.WithLabelValues(kind, result(err)).Inc()
+}
+
+// Delivery counts one outbox delivery attempt; result is "sent", "failed"
+// (will be retried) or "dead" (given up).
+func (m *Metrics) Delivery(notifier, result string) {
+	if m == nil {
+		return
+	}
+	m.deliveries.WithLabelValues(notifier, result).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "sent"
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	if rr.Code != 200 {
+		t.Fatalf("scrape: want 200 got %d", rr.Code)
+	}
+	b, _ := io.ReadAll(rr.Body)
+	return string(b)
+}
+
+func TestMetrics_ExposesProbeAndInternalSeries(t *testing.T) {
+	m := New()
+	m.ObserveCheck("t1", "https://a.example", true, false, 200, 42, 50*time.Millisecond)
+	m.ObserveCheck("t2", "tcp://db:5432", false, false, 0, 0, time.Second)
+	m.ObservePass(time.Millisecond)
+	m.SetQueueDepth(2)
+	m.CheckStarted()
+	m.RateLimited("public")()
+	m.StoreError("append_result")
+	m.Notification("down", nil)
+	m.Notification("down", errors.New("boom"))
+	m.Delivery("slack", "dead")
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`uptime_target_up{target_id="t1",url="https://a.example"} 1`,
+		`uptime_target_up{target_id="t2",url="tcp://db:5432"} 0`,
+		`uptime_target_latency_ms{target_id="t1",url="https://a.example"} 42`,
+		`uptime_target_http_status{target_id="t1",url="https://a.example"} 200`,
+		`uptime_checks_total{outcome="up",target_id="t1"} 1`,
+		`uptime_checks_total{outcome="down",target_id="t2"} 1`,
+		`uptime_check_duration_seconds_count{target_id="t1"} 1`,
+		`uptime_rechecker_queue_depth 2`,
+		`uptime_rechecker_inflight_checks 1`,
+		`uptime_http_rate_limited_total{group="public"} 1`,
+		`uptime_store_errors_total{op="append_result"} 1`,
+		`uptime_alerter_notifications_total{kind="down",result="sent"} 1`,
+		`uptime_alerter_notifications_total{kind="down",result="failed"} 1`,
+		`uptime_outbox_deliveries_total{notifier="slack",result="dead"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+}
+
+func TestMetrics_ForgetTargetDropsSeries(t *testing.T) {
+	m := New()
+	m.ObserveCheck("t1", "https://a.example", true, false, 200, 42, time.Millisecond)
+	m.ForgetTarget("t1")
+	if out := scrape(t, m); strings.Contains(out, `target_id="t1"`) {
+		t.Fatalf("series for deleted target still exported:\n%s", out)
+	}
+}
+
+func TestMetrics_NilIsNoop(t *testing.T) {
+	var m *Metrics
+	m.ObserveCheck("t1", "u", true, false, 200, 1, time.Millisecond)
+	m.ForgetTarget("t1")
+	m.ObservePass(time.Millisecond)
+	m.SetQueueDepth(1)
+	m.CheckStarted()
+	m.CheckFinished()
+	if f := m.RateLimited("public"); f != nil {
+		t.Fatal("nil Metrics should return a nil reject callback")
+	}
+	m.StoreError("x")
+	m.Notification("down", nil)
+	m.Delivery("slack", "sent")
+}
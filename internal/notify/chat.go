@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"net/url"
+	"strings"
+)
+
+// fact is one "Name: value" line of an alert's text.
+type fact struct {
+	Name  string
+	Value string
+}
+
+// splitFacts separates the "Name: value" lines the alerter writes from any
+// free-form lines, so chat notifiers can render them as tables or fields.
+func splitFacts(text string) (facts []fact, other []string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok || name == "" || len(name) > 24 { // a sentence, not a label
+			other = append(other, line)
+			continue
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			facts = append(facts, fact{Name: name, Value: value})
+		}
+	}
+	return facts, other
+}
+
+// Severity buckets used to colour chat messages.
+const (
+	sevDown    = "down"
+	sevUp      = "up"
+	sevWarning = "warning"
+	sevInfo    = "info"
+)
+
+func severityOf(kind string) string {
+	switch kind {
+	case KindDown, KindEscalated:
+		return sevDown
+	case KindRecovered, KindDegradedRecovered, KindFlappingEnded:
+		return sevUp
+	case KindDegraded, KindFlapping, KindCertExpiring:
+		return sevWarning
+	}
+	return sevInfo
+}
+
+// targetLink is the target's URL when chat clients and PagerDuty can open
+// it, i.e. an http(s) URL; tcp:// and dns:// targets get no link, since
+// Discord rejects the whole message over an invalid embed URL.
+func targetLink(ev Event) string {
+	if ev.Target == nil || !isWebURL(ev.Target.URL) {
+		return ""
+	}
+	return ev.Target.URL
+}
+
+// isWebURL reports whether s is an http(s) URL with a host.
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Host != "" && (u.Scheme == "http" || u.Scheme == "https")
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Discord posts embeds, coloured by state, to a Discord channel webhook.
+type Discord struct {
+	Webhook string
+	Client  *http.Client
+}
+
+func NewDiscord(webhook string) *Discord {
+	if webhook == "" {
+		return nil
+	}
+	return &Discord{
+		Webhook: webhook,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+var discordColors = map[string]int{
+	sevDown:    0xD92D20,
+	sevUp:      0x12B76A,
+	sevWarning: 0xF79009,
+	sevInfo:    0x98A2B3,
+}
+
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+func (d *Discord) Send(ctx context.Context, title, text string) error {
+	return d.Notify(ctx, Event{Title: title, Text: text, At: time.Now().UTC()})
+}
+
+func (d *Discord) Notify(ctx context.Context, ev Event) error {
+	return postJSON(ctx, d.Client, "discord", d.Webhook, nil, discordMessageFor(ev))
+}
+
+func discordMessageFor(ev Event) discordMessage {
+	facts, other := splitFacts(ev.Text)
+	e := discordEmbed{
+		Title:       truncate(ev.Title, 256),
+		Description: truncate(strings.Join(other, "\n"), 4096),
+		Color:       discordColors[severityOf(ev.Kind)],
+	}
+	e.URL = targetLink(ev)
+	if !ev.At.IsZero() {
+		e.Timestamp = ev.At.Format(time.RFC3339)
+	}
+	for _, f := range facts {
+		if len(e.Fields) == 25 { // Discord's per-embed limit
+			break
+		}
+		e.Fields = append(e.Fields, discordField{
+			Name:   truncate(f.Name, 256),
+			Value:  truncate(f.Value, 1024),
+			Inline: f.Name != "URL" && len(f.Value) <= 40,
+		})
+	}
+	return discordMessage{Username: "uptimechecker", Embeds: []discordEmbed{e}}
+}
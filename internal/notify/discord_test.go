@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+func TestDiscord_EmbedColouredByState(t *testing.T) {
+	var got discordMessage
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	ev := sampleEvent()
+	ev.Text += "\nConfirmed by: 3 consecutive checks"
+	if err := NewDiscord(ts.URL).Notify(context.Background(), ev); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(got.Embeds) != 1 {
+		t.Fatalf("want one embed, got %+v", got)
+	}
+	e := got.Embeds[0]
+	if e.Title != "🔴 Target DOWN" || e.Color != discordColors[sevDown] || e.URL != "https://a.example" {
+		t.Fatalf("unexpected embed: %+v", e)
+	}
+	if len(e.Fields) != 3 || e.Fields[0].Name != "URL" || e.Fields[0].Inline || e.Fields[2].Value != "3 consecutive checks" {
+		t.Fatalf("fields: %+v", e.Fields)
+	}
+
+	for kind, sev := range map[string]string{KindRecovered: sevUp, KindDegraded: sevWarning} {
+		if c := discordMessageFor(Event{Kind: kind}).Embeds[0].Color; c != discordColors[sev] {
+			t.Errorf("%s: color %#x", kind, c)
+		}
+	}
+}
+
+func TestTargetLink_OnlyHTTP(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://a.example":  "https://a.example",
+		"http://a.example/x": "http://a.example/x",
+		"tcp://db:5432":      "",
+		"dns://example.com":  "",
+	} {
+		ev := Event{Kind: KindDown, Target: &domain.Target{ID: "A", URL: raw}}
+		if got := discordMessageFor(ev).Embeds[0].URL; got != want {
+			t.Errorf("discord %s: url %q, want %q", raw, got, want)
+		}
+		card := teamsMessage(ev)["attachments"].([]map[string]any)[0]["content"].(map[string]any)
+		if _, ok := card["actions"]; ok != (want != "") {
+			t.Errorf("teams %s: actions present = %v", raw, ok)
+		}
+	}
+	if targetLink(Event{}) != "" {
+		t.Error("no target must give no link")
+	}
+}
+
+func TestDiscord_Non2xx(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Invalid Webhook Token"}`, http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+	if err := NewDiscord(ts.URL).Send(context.Background(), "t", "x"); err == nil {
+		t.Fatal("want error for 401")
+	}
+	if NewDiscord("") != nil {
+		t.Fatal("empty webhook must disable Discord")
+	}
+}
@@ -0,0 +1,238 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP transport security modes.
+const (
+	SMTPStartTLS = "starttls" // plain connection upgraded with STARTTLS (required)
+	SMTPTLS      = "tls"      // implicit TLS, usually port 465
+	SMTPNone     = "none"     // no encryption; local relays only
+)
+
+// EmailConfig describes the SMTP relay and recipients.
+type EmailConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"` // empty disables AUTH
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	TLS      string   `json:"tls"` // SMTPStartTLS (default), SMTPTLS or SMTPNone
+}
+
+// Email sends alerts as multipart (plain text + HTML) mail over SMTP.
+type Email struct {
+	cfg     EmailConfig
+	Timeout time.Duration
+	// TLSConfig overrides the default (verify against system roots, ServerName = Host).
+	TLSConfig *tls.Config
+}
+
+func NewEmail(cfg EmailConfig) (*Email, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("email: host is required")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("email: from and at least one recipient are required")
+	}
+	if cfg.TLS == "" {
+		cfg.TLS = SMTPStartTLS
+	}
+	switch cfg.TLS {
+	case SMTPStartTLS, SMTPTLS, SMTPNone:
+	default:
+		return nil, fmt.Errorf("email: unknown tls mode %q", cfg.TLS)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+		if cfg.TLS == SMTPTLS {
+			cfg.Port = 465
+		}
+	}
+	return &Email{cfg: cfg, Timeout: 15 * time.Second}, nil
+}
+
+func (e *Email) Send(ctx context.Context, title, text string) error {
+	return e.Notify(ctx, Event{Title: title, Text: text, At: time.Now().UTC()})
+}
+
+func (e *Email) Notify(ctx context.Context, ev Event) error {
+	msg, err := e.message(ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.Timeout)
+	defer cancel()
+
+	c, err := e.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("email: connect: %w", err)
+	}
+	defer c.Close()
+
+	if e.cfg.TLS == SMTPStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("email: server does not support STARTTLS")
+		}
+		if err := c.StartTLS(e.tlsConfig()); err != nil {
+			return fmt.Errorf("email: starttls: %w", err)
+		}
+	}
+	if e.cfg.Username != "" {
+		auth := smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("email: auth: %w", err)
+		}
+	}
+	if err := c.Mail(e.cfg.From); err != nil {
+		return fmt.Errorf("email: mail from: %w", err)
+	}
+	for _, to := range e.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("email: rcpt %s: %w", to, err)
+		}
+	}
+	wc, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("email: data: %w", err)
+	}
+	if _, err := wc.Write(msg); err != nil {
+		return fmt.Errorf("email: write: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("email: data: %w", err)
+	}
+	return c.Quit()
+}
+
+func (e *Email) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	d := &net.Dialer{}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if e.cfg.TLS == SMTPTLS {
+		conn, err = (&tls.Dialer{NetDialer: d, Config: e.tlsConfig()}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// net/smtp has no context support; bound the whole exchange instead.
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (e *Email) tlsConfig() *tls.Config {
+	if e.TLSConfig != nil {
+		return e.TLSConfig
+	}
+	return &tls.Config{ServerName: e.cfg.Host, MinVersion: tls.VersionTLS12}
+}
+
+var emailHTML = template.Must(template.New("email").Funcs(template.FuncMap{
+	"deref": func(p *float64) float64 { return *p },
+}).Parse(`<!doctype html>
+<html><body style="font-family: system-ui, sans-serif; color: #1f2328">
+<h2 style="margin: 0 0 12px">{{.Title}}</h2>
+<table cellpadding="4" style="border-collapse: collapse">
+{{- if .Target}}<tr><td><b>Target</b></td><td>{{.Target.URL}}</td></tr>{{end}}
+{{- if .PreviousState}}<tr><td><b>Previous state</b></td><td>{{.PreviousState}}</td></tr>{{end}}
+{{- with .Result}}
+<tr><td><b>HTTP</b></td><td>{{if .HTTPStatus}}{{.HTTPStatus}}{{else}}n/a{{end}}</td></tr>
+<tr><td><b>Latency</b></td><td>{{if .LatencyMS}}{{printf "%.0f ms" (deref .LatencyMS)}}{{else}}n/a{{end}}</td></tr>
+{{- if .Reason}}<tr><td><b>Reason</b></td><td>{{.Reason}}</td></tr>{{end}}
+<tr><td><b>Checked</b></td><td>{{.CheckedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+{{- end}}
+{{- with .Incident}}<tr><td><b>Incident</b></td><td>#{{.ID}} since {{.StartedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>{{end}}
+</table>
+<pre style="margin-top: 16px; color: #656d76">{{.Text}}</pre>
+</body></html>
+`))
+
+// message builds the RFC 5322 message with plain-text and HTML parts.
+func (e *Email) message(ev Event) ([]byte, error) {
+	var html bytes.Buffer
+	if err := emailHTML.Execute(&html, ev); err != nil {
+		return nil, fmt.Errorf("email: render: %w", err)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ ctype, content string }{
+		{"text/plain; charset=utf-8", ev.Title + "\n\n" + ev.Text + "\n"},
+		{"text/html; charset=utf-8", html.String()},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.ctype},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	at := ev.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	var msg bytes.Buffer
+	hdr := func(k, v string) { fmt.Fprintf(&msg, "%s: %s\r\n", k, v) }
+	hdr("From", e.cfg.From)
+	hdr("To", strings.Join(e.cfg.To, ", "))
+	hdr("Subject", mime.QEncoding.Encode("utf-8", ev.Title))
+	hdr("Date", at.Format(time.RFC1123Z))
+	hdr("Message-ID", messageID(e.cfg.From))
+	hdr("MIME-Version", "1.0")
+	hdr("Content-Type", `multipart/alternative; boundary="`+mw.Boundary()+`"`)
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "uptimechecker.local"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = strings.Trim(from[i+1:], "> ")
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
@@ -0,0 +1,262 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTP is a minimal single-session SMTP server recording what it receives.
+type fakeSMTP struct {
+	ln       net.Listener
+	tls      *tls.Config // enables STARTTLS when set
+	auth     string      // decoded AUTH PLAIN credentials
+	from     string
+	rcpts    []string
+	data     string
+	startTLS bool
+	done     chan struct{}
+}
+
+func newFakeSMTP(t *testing.T, tlsCfg *tls.Config) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{ln: ln, tls: tlsCfg, done: make(chan struct{})}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *fakeSMTP) port() int { return s.ln.Addr().(*net.TCPAddr).Port }
+
+func (s *fakeSMTP) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	r := bufio.NewReader(conn)
+	w := io.Writer(conn)
+	reply := func(line string) { _, _ = io.WriteString(w, line+"\r\n") }
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(cmd, " ", 2)[0])
+		switch verb {
+		case "EHLO":
+			if s.tls != nil && !s.startTLS {
+				reply("250-fake")
+				reply("250-STARTTLS")
+			} else {
+				reply("250-fake")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 go ahead")
+			tc := tls.Server(conn, s.tls)
+			if err := tc.Handshake(); err != nil {
+				return
+			}
+			s.startTLS = true
+			r, w = bufio.NewReader(tc), tc
+		case "AUTH":
+			parts := strings.Fields(cmd)
+			raw, _ := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+			s.auth = string(raw)
+			reply("235 ok")
+		case "MAIL":
+			s.from = strings.Trim(strings.TrimPrefix(cmd, "MAIL FROM:"), "<>")
+			reply("250 ok")
+		case "RCPT":
+			s.rcpts = append(s.rcpts, strings.Trim(strings.TrimPrefix(cmd, "RCPT TO:"), "<>"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 send")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data = b.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// selfSigned returns a server config for 127.0.0.1 and a pool trusting it.
+func selfSigned(t *testing.T) (*tls.Config, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, pool
+}
+
+func TestEmail_PlainMultipartMessage(t *testing.T) {
+	srv := newFakeSMTP(t, nil)
+	e, err := NewEmail(EmailConfig{
+		Host: "127.0.0.1",
+		Port: srv.port(),
+		From: "uptime@example.com",
+		To:   []string{"ops@example.com", "oncall@example.com"},
+		TLS:  SMTPNone,
+	})
+	if err != nil {
+		t.Fatalf("NewEmail: %v", err)
+	}
+	if err := e.Notify(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	<-srv.done
+
+	if srv.from != "uptime@example.com" || strings.Join(srv.rcpts, ",") != "ops@example.com,oncall@example.com" {
+		t.Fatalf("envelope: from=%q rcpts=%v", srv.from, srv.rcpts)
+	}
+	if srv.auth != "" {
+		t.Fatal("AUTH must not be sent without a username")
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "🔴 Target DOWN" {
+		t.Fatalf("subject = %q", subject)
+	}
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q", mediaType)
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart() // decodes quoted-printable
+		if err != nil {
+			break
+		}
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		b, _ := io.ReadAll(p)
+		parts[ct] = string(b)
+	}
+	if !strings.Contains(parts["text/plain"], "URL: https://a.example") {
+		t.Fatalf("text part: %q", parts["text/plain"])
+	}
+	if !strings.Contains(parts["text/html"], "<td>https://a.example</td>") ||
+		!strings.Contains(parts["text/html"], "<td>503</td>") ||
+		!strings.Contains(parts["text/html"], "#7 since") {
+		t.Fatalf("html part: %q", parts["text/html"])
+	}
+}
+
+func TestEmail_StartTLSAndAuth(t *testing.T) {
+	serverTLS, pool := selfSigned(t)
+	srv := newFakeSMTP(t, serverTLS)
+	e, err := NewEmail(EmailConfig{
+		Host:     "127.0.0.1",
+		Port:     srv.port(),
+		Username: "bot",
+		Password: "pw",
+		From:     "uptime@example.com",
+		To:       []string{"ops@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("NewEmail: %v", err)
+	}
+	e.TLSConfig = &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+	if err := e.Send(context.Background(), "hello", "world"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	<-srv.done
+	if !srv.startTLS {
+		t.Fatal("connection was not upgraded")
+	}
+	if srv.auth != "\x00bot\x00pw" {
+		t.Fatalf("auth = %q", srv.auth)
+	}
+	if !strings.Contains(srv.data, "Subject: hello") {
+		t.Fatalf("message: %q", srv.data)
+	}
+}
+
+func TestEmail_StartTLSRequired(t *testing.T) {
+	srv := newFakeSMTP(t, nil) // does not offer STARTTLS
+	e, _ := NewEmail(EmailConfig{Host: "127.0.0.1", Port: srv.port(), From: "a@x", To: []string{"b@x"}})
+	err := e.Send(context.Background(), "t", "x")
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("want STARTTLS error, got %v", err)
+	}
+}
+
+func TestNewEmail_Validation(t *testing.T) {
+	for name, cfg := range map[string]EmailConfig{
+		"no host":      {From: "a@x", To: []string{"b@x"}},
+		"no from":      {Host: "smtp.x", To: []string{"b@x"}},
+		"no recipient": {Host: "smtp.x", From: "a@x"},
+		"bad tls":      {Host: "smtp.x", From: "a@x", To: []string{"b@x"}, TLS: "ssl"},
+	} {
+		if _, err := NewEmail(cfg); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+	e, _ := NewEmail(EmailConfig{Host: "smtp.x", From: "a@x", To: []string{"b@x"}, TLS: SMTPTLS})
+	if e.cfg.Port != 465 {
+		t.Errorf("implicit TLS default port = %d", e.cfg.Port)
+	}
+	e, _ = NewEmail(EmailConfig{Host: "smtp.x", From: "a@x", To: []string{"b@x"}})
+	if e.cfg.TLS != SMTPStartTLS || e.cfg.Port != 587 {
+		t.Errorf("defaults = %s:%d", e.cfg.TLS, e.cfg.Port)
+	}
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+// Alert kinds sent by the alerter.
+const (
+	KindDown              = "down"
+	KindRecovered         = "recovered"
+	KindFlapping          = "flapping"
+	KindFlappingEnded     = "flapping_ended"
+	KindDegraded          = "degraded"
+	KindDegradedRecovered = "degraded_recovered"
+	KindCertExpiring      = "cert_expiring"
+	KindEscalated         = "escalated"
+)
+
+// Event is the structured form of an alert. Title and Text are the
+// human-readable message every notifier can send; the other fields let
+// integrations build their own payloads.
+type Event struct {
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	// Target is redacted (no credentials).
+	Target *domain.Target `json:"target"`
+	Result *domain.Result `json:"result,omitempty"`
+	// PreviousState is "up", "down" or "" if nothing was recorded before.
+	PreviousState string           `json:"previous_state"`
+	Incident      *domain.Incident `json:"incident,omitempty"`
+	At            time.Time        `json:"at"`
+	// ResolveOnly marks a recovery sent only so paging notifiers close their
+	// incident (recovery alerts are turned off); other notifiers skip it.
+	ResolveOnly bool `json:"resolve_only,omitempty"`
+}
+
+// Resolves reports whether ev ends an outage: a recovery, or flapping that
+// settled with the target up.
+func (ev Event) Resolves() bool {
+	switch ev.Kind {
+	case KindRecovered:
+		return true
+	case KindFlappingEnded:
+		return ev.Result != nil && ev.Result.Up
+	}
+	return false
+}
+
+// EventNotifier is implemented by notifiers that use the structured event
+// rather than just its title and text.
+type EventNotifier interface {
+	Notifier
+	Notify(ctx context.Context, ev Event) error
+}
+
+// Deliver sends ev through n, as a structured event if n supports it.
+func Deliver(ctx context.Context, n Notifier, ev Event) error {
+	if en, ok := n.(EventNotifier); ok {
+		return en.Notify(ctx, ev)
+	}
+	return n.Send(ctx, ev.Title, ev.Text)
+}
+
+// Notify delivers ev to every notifier, returning the first error.
+// ResolveOnly events only go to paging notifiers.
+func (m Multi) Notify(ctx context.Context, ev Event) error {
+	var firstErr error
+	for _, n := range m {
+		if n == nil || (ev.ResolveOnly && !pages(n)) {
+			continue
+		}
+		if err := Deliver(ctx, n, ev); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const opsgenieURL = "https://api.opsgenie.com"
+
+// Opsgenie creates and closes alerts through the Alert API, using the
+// dedup key as the alert alias.
+type Opsgenie struct {
+	APIKey string
+	URL    string // API base; https://api.eu.opsgenie.com for the EU instance
+	Client *http.Client
+}
+
+func NewOpsgenie(apiKey, baseURL string) *Opsgenie {
+	if apiKey == "" {
+		return nil
+	}
+	if baseURL == "" {
+		baseURL = opsgenieURL
+	}
+	return &Opsgenie{
+		APIKey: apiKey,
+		URL:    strings.TrimRight(baseURL, "/"),
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type ogAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Priority    string            `json:"priority"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+type ogClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Send is a no-op: without a structured event there is nothing to dedup on.
+func (o *Opsgenie) Send(ctx context.Context, title, text string) error { return nil }
+
+func (o *Opsgenie) Notify(ctx context.Context, ev Event) error {
+	pg, ok := pageFor(ev)
+	if !ok {
+		return nil
+	}
+	header := http.Header{"Authorization": {"GenieKey " + o.APIKey}}
+	if pg.resolve {
+		u := o.URL + "/v2/alerts/" + url.PathEscape(pg.key) + "/close?identifierType=alias"
+		return postJSON(ctx, o.Client, "opsgenie", u, header, ogClose{Source: "uptimechecker", Note: ev.Title})
+	}
+	a := ogAlert{
+		Message:     truncate(ev.Title+": "+ev.Target.URL, 130),
+		Alias:       pg.key,
+		Description: truncate(ev.Text, 15000),
+		Priority:    "P1",
+		Source:      "uptimechecker",
+		Entity:      ev.Target.URL,
+		Tags:        []string{"uptimechecker", ev.Kind},
+		Details:     map[string]string{},
+	}
+	if pg.severity == "warning" {
+		a.Priority = "P3"
+	}
+	for k, v := range pageDetails(ev) {
+		a.Details[k] = fmt.Sprint(v)
+	}
+	return postJSON(ctx, o.Client, "opsgenie", o.URL+"/v2/alerts", header, a)
+}
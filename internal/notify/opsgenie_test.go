@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpsgenie_CreateThenCloseByAlias(t *testing.T) {
+	type call struct {
+		path, query, auth string
+		body              map[string]any
+	}
+	var calls []call
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := call{path: r.URL.EscapedPath(), query: r.URL.RawQuery, auth: r.Header.Get("Authorization")}
+		_ = json.NewDecoder(r.Body).Decode(&c.body)
+		calls = append(calls, c)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	og := NewOpsgenie("key", ts.URL+"/")
+	if err := og.Notify(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	up := sampleEvent()
+	up.Kind = KindRecovered
+	up.Title = "🟢 Target RECOVERED"
+	if err := og.Notify(context.Background(), up); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("want 2 calls, got %d", len(calls))
+	}
+	create, closeCall := calls[0], calls[1]
+	if create.path != "/v2/alerts" || create.auth != "GenieKey key" || create.body["priority"] != "P1" ||
+		create.body["alias"] != "uptimechecker-t1" || create.body["entity"] != "https://a.example" {
+		t.Fatalf("unexpected create: %+v", create)
+	}
+	if details, _ := create.body["details"].(map[string]any); details["http_status"] != "503" {
+		t.Fatalf("details: %v", create.body["details"])
+	}
+	if closeCall.path != "/v2/alerts/uptimechecker-t1/close" || closeCall.query != "identifierType=alias" ||
+		closeCall.auth != "GenieKey key" || closeCall.body["note"] != "🟢 Target RECOVERED" {
+		t.Fatalf("unexpected close: %+v", closeCall)
+	}
+}
+
+func TestOpsgenie_DefaultsAndErrors(t *testing.T) {
+	if NewOpsgenie("", "") != nil {
+		t.Fatal("empty api key must disable Opsgenie")
+	}
+	if og := NewOpsgenie("k", ""); og.URL != opsgenieURL {
+		t.Fatalf("default url = %q", og.URL)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Key format is not valid!"}`, http.StatusUnprocessableEntity)
+	}))
+	defer ts.Close()
+	if err := NewOpsgenie("k", ts.URL).Notify(context.Background(), sampleEvent()); err == nil {
+		t.Fatal("want error for 422")
+	}
+}
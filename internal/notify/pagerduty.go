@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty triggers and resolves incidents through the Events API v2.
+type PagerDuty struct {
+	RoutingKey string
+	URL        string
+	Client     *http.Client
+}
+
+func NewPagerDuty(routingKey string) *PagerDuty {
+	if routingKey == "" {
+		return nil
+	}
+	return &PagerDuty{
+		RoutingKey: routingKey,
+		URL:        pagerDutyEventsURL,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type pdEvent struct {
+	RoutingKey  string     `json:"routing_key"`
+	EventAction string     `json:"event_action"`
+	DedupKey    string     `json:"dedup_key"`
+	Payload     *pdPayload `json:"payload,omitempty"`
+	Links       []pdLink   `json:"links,omitempty"`
+}
+
+type pdPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Group         string         `json:"group,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+type pdLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// Send is a no-op: without a structured event there is nothing to dedup on.
+func (p *PagerDuty) Send(ctx context.Context, title, text string) error { return nil }
+
+func (p *PagerDuty) Notify(ctx context.Context, ev Event) error {
+	pg, ok := pageFor(ev)
+	if !ok {
+		return nil
+	}
+	e := pdEvent{RoutingKey: p.RoutingKey, EventAction: "trigger", DedupKey: pg.key}
+	if pg.resolve {
+		e.EventAction = "resolve"
+	} else {
+		e.Payload = &pdPayload{
+			Summary:       truncate(ev.Title+": "+ev.Target.URL, 1024),
+			Source:        ev.Target.URL,
+			Severity:      pg.severity,
+			Component:     string(ev.Target.ID),
+			Group:         ev.Target.Group,
+			Class:         ev.Kind,
+			CustomDetails: pageDetails(ev),
+		}
+		if !ev.At.IsZero() {
+			e.Payload.Timestamp = ev.At.Format(time.RFC3339)
+		}
+		if link := targetLink(ev); link != "" {
+			e.Links = []pdLink{{Href: link, Text: "Target"}}
+		}
+	}
+	return postJSON(ctx, p.Client, "pagerduty", p.URL, nil, e)
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
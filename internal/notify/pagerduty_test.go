@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+func TestPagerDuty_TriggerThenResolveSameDedupKey(t *testing.T) {
+	var got []pdEvent
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e pdEvent
+		_ = json.NewDecoder(r.Body).Decode(&e)
+		got = append(got, e)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	pd := NewPagerDuty("rk")
+	pd.URL = ts.URL
+	down := sampleEvent()
+	if err := pd.Notify(context.Background(), down); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	up := sampleEvent()
+	up.Kind = KindRecovered
+	if err := pd.Notify(context.Background(), up); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("want 2 events, got %d", len(got))
+	}
+	trig, res := got[0], got[1]
+	if trig.EventAction != "trigger" || trig.RoutingKey != "rk" || trig.Payload == nil ||
+		trig.Payload.Severity != "critical" || trig.Payload.Source != "https://a.example" {
+		t.Fatalf("unexpected trigger: %+v %+v", trig, trig.Payload)
+	}
+	if trig.Payload.CustomDetails["http_status"] != float64(503) || trig.Payload.CustomDetails["incident_id"] != float64(7) {
+		t.Fatalf("custom details: %v", trig.Payload.CustomDetails)
+	}
+	if res.EventAction != "resolve" || res.Payload != nil {
+		t.Fatalf("unexpected resolve: %+v", res)
+	}
+	if trig.DedupKey == "" || trig.DedupKey != res.DedupKey {
+		t.Fatalf("dedup keys differ: %q vs %q", trig.DedupKey, res.DedupKey)
+	}
+}
+
+func TestPagerDuty_SkipsUnpagedKindsAndReportsErrors(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, `{"status":"invalid event"}`, http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	pd := NewPagerDuty("rk")
+	pd.URL = ts.URL
+	flap := sampleEvent()
+	flap.Kind = KindFlapping
+	if err := pd.Notify(context.Background(), flap); err != nil || calls != 0 {
+		t.Fatalf("flapping must not page: err=%v calls=%d", err, calls)
+	}
+	if err := pd.Notify(context.Background(), Event{Kind: KindDown}); err != nil || calls != 0 {
+		t.Fatalf("event without target must not page: err=%v calls=%d", err, calls)
+	}
+	if err := pd.Notify(context.Background(), sampleEvent()); err == nil {
+		t.Fatal("want error for 400")
+	}
+	if NewPagerDuty("") != nil {
+		t.Fatal("empty routing key must disable PagerDuty")
+	}
+}
+
+func TestPageFor_LatencyIsSeparateIncident(t *testing.T) {
+	ev := Event{Kind: KindDegraded, Target: &domain.Target{ID: "t1"}}
+	deg, _ := pageFor(ev)
+	ev.Kind = KindDown
+	down, _ := pageFor(ev)
+	if deg.key == down.key || deg.severity != "warning" {
+		t.Fatalf("degraded page = %+v, down page = %+v", deg, down)
+	}
+}
+
+func TestPageFor_FlappingSettledUpResolves(t *testing.T) {
+	ev := Event{Kind: KindFlappingEnded, Target: &domain.Target{ID: "t1"}, Result: &domain.Result{Up: true}}
+	if pg, ok := pageFor(ev); !ok || !pg.resolve || pg.key != "uptimechecker-t1" {
+		t.Fatalf("settled up: %+v %v", pg, ok)
+	}
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// page is what a paging integration (PagerDuty, Opsgenie) does with an event.
+type page struct {
+	resolve  bool
+	key      string // dedup key: one open incident per target and condition
+	severity string // "critical" or "warning"
+}
+
+// pageFor maps an alert to a trigger or resolve. Outages and latency
+// degradation are tracked as separate incidents, and an escalation triggers
+// the outage's incident (so a service first reached at tier 2 gets paged).
+// Flapping that settles up resolves the outage like a recovery; settling
+// down is paged by the DOWN alert the alerter sends after it. Other kinds
+// (flapping, certificate notices) and events without a target are not paged.
+func pageFor(ev Event) (page, bool) {
+	if ev.Target == nil || ev.Target.ID == "" {
+		return page{}, false
+	}
+	key := "uptimechecker-" + string(ev.Target.ID)
+	if ev.Resolves() {
+		return page{key: key, resolve: true}, true
+	}
+	switch ev.Kind {
+	case KindDown, KindEscalated:
+		return page{key: key, severity: "critical"}, true
+	case KindDegraded:
+		return page{key: key + "-latency", severity: "warning"}, true
+	case KindDegradedRecovered:
+		return page{key: key + "-latency", resolve: true}, true
+	}
+	return page{}, false
+}
+
+// pages reports whether n opens incidents that need a resolve.
+func pages(n Notifier) bool {
+	switch n.(type) {
+	case *PagerDuty, *Opsgenie:
+		return true
+	}
+	return false
+}
+
+// pageDetails collects the event fields worth showing in the paging tool.
+func pageDetails(ev Event) map[string]any {
+	d := map[string]any{"kind": ev.Kind, "text": ev.Text}
+	if ev.Target != nil {
+		d["url"] = ev.Target.URL
+	}
+	if r := ev.Result; r != nil {
+		if r.HTTPStatus != nil {
+			d["http_status"] = *r.HTTPStatus
+		}
+		if r.LatencyMS != nil {
+			d["latency_ms"] = *r.LatencyMS
+		}
+		if r.Reason != "" {
+			d["reason"] = r.Reason
+		}
+	}
+	if ev.Incident != nil {
+		d["incident_id"] = ev.Incident.ID
+	}
+	return d
+}
+
+// postJSON sends payload and treats any non-2xx status as an error.
+func postJSON(ctx context.Context, c *http.Client, name, url string, header http.Header, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: status %d: %s", name, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	return nil
+}
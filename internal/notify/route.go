@@ -0,0 +1,399 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultNotifiers names the notifiers configured through the environment
+// (SLACK_WEBHOOK_URL, SMTP_HOST, ...) in a routing file.
+const DefaultNotifiers = "default"
+
+// RoutingConfig is the routing file: named notifiers and the rules that pick
+// among them by target label and alert kind.
+type RoutingConfig struct {
+	// Notifiers maps a name to {"type": "...", ...type-specific settings}.
+	Notifiers map[string]json.RawMessage `json:"notifiers"`
+	Routes    []RouteConfig              `json:"routes"`
+	// Default lists the notifiers for alerts no route matches
+	// (empty = DefaultNotifiers).
+	Default []string `json:"default"`
+	// Escalations maps a policy name to the tiers notified, in order, while
+	// an outage stays unacknowledged. The route's own notifiers are tier 1.
+	Escalations map[string][]EscalationTier `json:"escalations"`
+	// DefaultEscalation applies when no matching route names a policy.
+	DefaultEscalation string `json:"default_escalation"`
+}
+
+// EscalationTier is notified once an incident has been open, and not
+// acknowledged, for AfterMinutes.
+type EscalationTier struct {
+	AfterMinutes int      `json:"after_minutes"`
+	Notifiers    []string `json:"notifiers"`
+}
+
+// After returns AfterMinutes as a duration.
+func (t EscalationTier) After() time.Duration {
+	return time.Duration(t.AfterMinutes) * time.Minute
+}
+
+// Escalator chooses escalation tiers for an alert and notifies them.
+type Escalator interface {
+	// Escalation returns the tiers for ev's target, or nil for none.
+	Escalation(ev Event) []EscalationTier
+	NotifyTier(ctx context.Context, tier EscalationTier, ev Event) error
+}
+
+// RouteConfig sends matching alerts to Notifiers. Routes are tried in order
+// and the first match wins unless it sets Continue.
+type RouteConfig struct {
+	// Match requires each target label to have the given value; "*" only
+	// requires the label to be set.
+	Match map[string]string `json:"match"`
+	// Kinds limits the route to some alert kinds (e.g. "down", "recovered").
+	Kinds     []string `json:"kinds"`
+	Notifiers []string `json:"notifiers"`
+	Continue  bool     `json:"continue"`
+	// Escalation names the policy for outages of matching targets.
+	Escalation string `json:"escalation"`
+}
+
+// Named is a notifier with the name routes and delivery logs refer to.
+type Named struct {
+	Name string
+	Notifier
+}
+
+// Router delivers each alert to the notifiers its route selects.
+type Router struct {
+	notifiers   map[string]Notifier
+	defaults    []string // names DefaultNotifiers expands to
+	routes      []RouteConfig
+	fallback    []string
+	escalations map[string][]EscalationTier
+	escalation  string // default policy
+}
+
+// NewRouter checks cfg and builds its notifiers. defaults is what
+// DefaultNotifiers refers to; their names must not clash with cfg's.
+func NewRouter(cfg RoutingConfig, defaults []Named) (*Router, error) {
+	r := &Router{
+		notifiers:   map[string]Notifier{},
+		routes:      cfg.Routes,
+		fallback:    cfg.Default,
+		escalations: cfg.Escalations,
+		escalation:  cfg.DefaultEscalation,
+	}
+	for _, d := range defaults {
+		if _, ok := r.notifiers[d.Name]; ok || d.Name == DefaultNotifiers {
+			return nil, fmt.Errorf("routing: duplicate notifier name %q", d.Name)
+		}
+		r.notifiers[d.Name] = d.Notifier
+		r.defaults = append(r.defaults, d.Name)
+	}
+	for name, raw := range cfg.Notifiers {
+		if name == DefaultNotifiers {
+			return nil, fmt.Errorf("routing: notifier name %q is reserved", name)
+		}
+		if _, ok := r.notifiers[name]; ok {
+			return nil, fmt.Errorf("routing: notifier name %q is already used by an environment-configured notifier", name)
+		}
+		n, err := buildNotifier(name, raw)
+		if err != nil {
+			return nil, fmt.Errorf("routing: notifier %s: %w", name, err)
+		}
+		r.notifiers[name] = n
+	}
+	if len(r.fallback) == 0 {
+		r.fallback = []string{DefaultNotifiers}
+	}
+	for i, rt := range cfg.Routes {
+		if len(rt.Notifiers) == 0 {
+			return nil, fmt.Errorf("routing: route %d has no notifiers", i+1)
+		}
+		if err := r.known(rt.Notifiers); err != nil {
+			return nil, fmt.Errorf("routing: route %d: %w", i+1, err)
+		}
+	}
+	if err := r.known(cfg.Default); err != nil {
+		return nil, fmt.Errorf("routing: default: %w", err)
+	}
+	for name, tiers := range cfg.Escalations {
+		if err := r.checkTiers(tiers); err != nil {
+			return nil, fmt.Errorf("routing: escalation %s: %w", name, err)
+		}
+	}
+	for i, rt := range cfg.Routes {
+		if _, ok := cfg.Escalations[rt.Escalation]; rt.Escalation != "" && !ok {
+			return nil, fmt.Errorf("routing: route %d: unknown escalation %q", i+1, rt.Escalation)
+		}
+	}
+	if _, ok := cfg.Escalations[cfg.DefaultEscalation]; cfg.DefaultEscalation != "" && !ok {
+		return nil, fmt.Errorf("routing: unknown default_escalation %q", cfg.DefaultEscalation)
+	}
+	return r, nil
+}
+
+// LoadRouting reads a JSON RoutingConfig from path.
+func LoadRouting(path string, defaults []Named) (*Router, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read routing: %w", err)
+	}
+	var cfg RoutingConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("parse routing: %w", err)
+	}
+	return NewRouter(cfg, defaults)
+}
+
+func (r *Router) known(names []string) error {
+	for _, n := range names {
+		if n == DefaultNotifiers {
+			if len(r.defaults) == 0 {
+				return errors.New("no notifiers are configured through the environment")
+			}
+			continue
+		}
+		if _, ok := r.notifiers[n]; !ok {
+			return fmt.Errorf("unknown notifier %q", n)
+		}
+	}
+	return nil
+}
+
+// checkTiers requires notifiers on every tier and strictly increasing delays.
+func (r *Router) checkTiers(tiers []EscalationTier) error {
+	if len(tiers) == 0 {
+		return errors.New("no tiers")
+	}
+	prev := 0
+	for i, t := range tiers {
+		if t.AfterMinutes <= prev {
+			return fmt.Errorf("tier %d: after_minutes must be greater than %d", i+2, prev)
+		}
+		prev = t.AfterMinutes
+		if len(t.Notifiers) == 0 {
+			return fmt.Errorf("tier %d has no notifiers", i+2)
+		}
+		if err := r.known(t.Notifiers); err != nil {
+			return fmt.Errorf("tier %d: %w", i+2, err)
+		}
+	}
+	return nil
+}
+
+// HasEscalations reports whether any escalation policy is defined.
+func (r *Router) HasEscalations() bool { return len(r.escalations) > 0 }
+
+// Escalation returns the policy of the first route whose labels match and
+// that names one (kinds are ignored), else the default policy.
+func (r *Router) Escalation(ev Event) []EscalationTier {
+	labels := eventLabels(ev)
+	for _, rt := range r.routes {
+		if rt.Escalation != "" && rt.matchesLabels(labels) {
+			return r.escalations[rt.Escalation]
+		}
+	}
+	return r.escalations[r.escalation]
+}
+
+// NotifyTier delivers ev to the tier's notifiers.
+func (r *Router) NotifyTier(ctx context.Context, tier EscalationTier, ev Event) error {
+	return r.deliver(ctx, r.TierNotifiers(tier), ev)
+}
+
+// TierNotifiers returns the names of the notifiers a tier goes to, each once.
+func (r *Router) TierNotifiers(tier EscalationTier) []string {
+	return r.expand(tier.Notifiers)
+}
+
+// Notifier returns the notifier with the given name.
+func (r *Router) Notifier(name string) (Notifier, bool) {
+	n, ok := r.notifiers[name]
+	return n, ok
+}
+
+// Len is the number of distinct notifiers alerts can be routed to.
+func (r *Router) Len() int { return len(r.notifiers) }
+
+// Route returns the names of the notifiers ev goes to, each once. Recoveries
+// also go to the escalation tiers paged for their incident; ResolveOnly
+// events only go to paging notifiers.
+func (r *Router) Route(ev Event) []string {
+	labels := eventLabels(ev)
+	var names []string
+	matched := false
+	for _, rt := range r.routes {
+		if !rt.matches(ev.Kind, labels) {
+			continue
+		}
+		names = append(names, rt.Notifiers...)
+		matched = true
+		if !rt.Continue {
+			break
+		}
+	}
+	if !matched {
+		names = r.fallback
+	}
+	names = r.expand(append(names, r.escalatedTo(ev)...))
+	if !ev.ResolveOnly {
+		return names
+	}
+	var out []string
+	for _, n := range names {
+		if pages(r.notifiers[n]) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// escalatedTo returns the notifiers of the escalation tiers already paged
+// for the incident a recovery ends, so they can resolve it too.
+func (r *Router) escalatedTo(ev Event) []string {
+	if !ev.Resolves() || ev.Incident == nil || ev.Incident.Escalation == 0 {
+		return nil
+	}
+	tiers := r.Escalation(ev)
+	var names []string
+	for i := 0; i < ev.Incident.Escalation && i < len(tiers); i++ {
+		names = append(names, tiers[i].Notifiers...)
+	}
+	return names
+}
+
+func eventLabels(ev Event) map[string]string {
+	if ev.Target == nil {
+		return nil
+	}
+	return ev.Target.Labels
+}
+
+// expand replaces DefaultNotifiers with the environment-configured names and
+// drops repeats, keeping the first occurrence.
+func (r *Router) expand(names []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	add := func(n string) {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	for _, n := range names {
+		if n != DefaultNotifiers {
+			add(n)
+			continue
+		}
+		for _, d := range r.defaults {
+			add(d)
+		}
+	}
+	return out
+}
+
+func (rt RouteConfig) matches(kind string, labels map[string]string) bool {
+	if len(rt.Kinds) > 0 {
+		ok := false
+		for _, k := range rt.Kinds {
+			ok = ok || k == kind
+		}
+		if !ok {
+			return false
+		}
+	}
+	return rt.matchesLabels(labels)
+}
+
+func (rt RouteConfig) matchesLabels(labels map[string]string) bool {
+	for k, want := range rt.Match {
+		got, ok := labels[k]
+		if !ok || (want != "*" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Send has no target to route on and goes to the default notifiers.
+func (r *Router) Send(ctx context.Context, title, text string) error {
+	return r.Notify(ctx, Event{Title: title, Text: text})
+}
+
+func (r *Router) Notify(ctx context.Context, ev Event) error {
+	return r.deliver(ctx, r.Route(ev), ev)
+}
+
+func (r *Router) deliver(ctx context.Context, names []string, ev Event) error {
+	var m Multi
+	for _, name := range names {
+		m = append(m, r.notifiers[name])
+	}
+	return m.Notify(ctx, ev)
+}
+
+// buildNotifier creates the notifier called name from {"type": "...", ...}.
+func buildNotifier(name string, raw json.RawMessage) (Notifier, error) {
+	var c struct {
+		Type       string `json:"type"`
+		URL        string `json:"url"`
+		Token      string `json:"token"`
+		ChatID     string `json:"chat_id"`
+		RoutingKey string `json:"routing_key"`
+		APIKey     string `json:"api_key"`
+		APIURL     string `json:"api_url"`
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	switch c.Type {
+	case "slack":
+		if n := NewSlack(c.URL); n != nil {
+			return n, nil
+		}
+	case "teams":
+		if n := NewTeams(c.URL); n != nil {
+			return n, nil
+		}
+	case "discord":
+		if n := NewDiscord(c.URL); n != nil {
+			return n, nil
+		}
+	case "telegram":
+		if n := NewTelegram(c.Token, c.ChatID); n != nil {
+			return n, nil
+		}
+	case "pagerduty":
+		if n := NewPagerDuty(c.RoutingKey); n != nil {
+			return n, nil
+		}
+	case "opsgenie":
+		if n := NewOpsgenie(c.APIKey, c.APIURL); n != nil {
+			return n, nil
+		}
+	case "webhook":
+		var wc WebhookConfig
+		if err := json.Unmarshal(raw, &wc); err != nil {
+			return nil, err
+		}
+		if wc.Name == "" {
+			wc.Name = name
+		}
+		return NewWebhook(wc)
+	case "email":
+		var ec EmailConfig
+		if err := json.Unmarshal(raw, &ec); err != nil {
+			return nil, err
+		}
+		return NewEmail(ec)
+	default:
+		return nil, fmt.Errorf("unknown type %q", c.Type)
+	}
+	return nil, fmt.Errorf("%s: missing required settings", c.Type)
+}
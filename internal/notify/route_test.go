@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+type recorder struct{ got []Event }
+
+func (r *recorder) Send(ctx context.Context, title, text string) error {
+	r.got = append(r.got, Event{Title: title, Text: text})
+	return nil
+}
+
+func (r *recorder) Notify(ctx context.Context, ev Event) error {
+	r.got = append(r.got, ev)
+	return nil
+}
+
+const routingJSON = `{
+  "notifiers": {
+    "payments-pd":   {"type": "pagerduty", "routing_key": "rk"},
+    "staging-slack": {"type": "slack", "url": "https://hooks.slack.test/staging"},
+    "critical-chat": {"type": "discord", "url": "https://discord.test/x"}
+  },
+  "routes": [
+    {"match": {"severity": "critical"}, "kinds": ["down"], "notifiers": ["critical-chat"], "continue": true},
+    {"match": {"team": "payments", "env": "prod"}, "notifiers": ["payments-pd", "default"]},
+    {"match": {"env": "staging"}, "notifiers": ["staging-slack"]},
+    {"match": {"oncall": "*"}, "notifiers": ["critical-chat"]}
+  ]
+}`
+
+// env is a stand-in for the environment-configured notifiers.
+func env(n Notifier) []Named { return []Named{{Name: "env", Notifier: n}} }
+
+func routedEvent(kind string, labels map[string]string) Event {
+	return Event{Kind: kind, Target: &domain.Target{ID: "t1", Labels: labels}}
+}
+
+func TestRouter_Route(t *testing.T) {
+	var cfg RoutingConfig
+	if err := json.Unmarshal([]byte(routingJSON), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRouter(cfg, env(&recorder{}))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	for name, tc := range map[string]struct {
+		ev   Event
+		want []string
+	}{
+		"payments prod":        {routedEvent(KindDown, map[string]string{"team": "payments", "env": "prod"}), []string{"payments-pd", "env"}},
+		"payments staging":     {routedEvent(KindDown, map[string]string{"team": "payments", "env": "staging"}), []string{"staging-slack"}},
+		"critical continues":   {routedEvent(KindDown, map[string]string{"severity": "critical", "env": "staging"}), []string{"critical-chat", "staging-slack"}},
+		"kind filter":          {routedEvent(KindRecovered, map[string]string{"severity": "critical"}), []string{"env"}},
+		"label present":        {routedEvent(KindDown, map[string]string{"oncall": "alice"}), []string{"critical-chat"}},
+		"dedup across matches": {routedEvent(KindDown, map[string]string{"severity": "critical", "oncall": "x"}), []string{"critical-chat"}},
+		"unlabelled":           {routedEvent(KindDown, nil), []string{"env"}},
+		"no target":            {Event{Kind: KindDown}, []string{"env"}},
+	} {
+		if got := r.Route(tc.ev); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, got, tc.want)
+		}
+	}
+	if r.Len() != 4 {
+		t.Errorf("Len = %d", r.Len())
+	}
+}
+
+func TestRouter_ResolveOnlyGoesToPagers(t *testing.T) {
+	var cfg RoutingConfig
+	_ = json.Unmarshal([]byte(routingJSON), &cfg)
+	r, err := NewRouter(cfg, env(&recorder{}))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	ev := routedEvent(KindRecovered, map[string]string{"team": "payments", "env": "prod"})
+	ev.ResolveOnly = true
+	if got := r.Route(ev); !reflect.DeepEqual(got, []string{"payments-pd"}) {
+		t.Fatalf("resolve-only route = %v", got)
+	}
+
+	chat := &recorder{}
+	if err := (Multi{chat}).Notify(context.Background(), ev); err != nil || len(chat.got) != 0 {
+		t.Fatalf("resolve-only event reached a chat notifier: %+v %v", chat.got, err)
+	}
+}
+
+func TestRouter_NotifyDeliversOnlyToRoute(t *testing.T) {
+	def, pay := &recorder{}, &recorder{}
+	r, err := NewRouter(RoutingConfig{
+		Routes: []RouteConfig{{Match: map[string]string{"team": "payments"}, Notifiers: []string{"default"}}},
+	}, env(def))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	r.notifiers["payments"] = pay
+	r.routes[0].Notifiers = []string{"payments"}
+
+	_ = r.Notify(context.Background(), routedEvent(KindDown, map[string]string{"team": "payments"}))
+	_ = r.Send(context.Background(), "plain", "text")
+	if len(pay.got) != 1 || pay.got[0].Kind != KindDown {
+		t.Fatalf("payments got %+v", pay.got)
+	}
+	if len(def.got) != 1 || def.got[0].Title != "plain" {
+		t.Fatalf("default got %+v", def.got)
+	}
+}
+
+func TestLoadRouting_Errors(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"unknown notifier": `{"routes": [{"match": {"env": "prod"}, "notifiers": ["nope"]}]}`,
+		"empty route":      `{"routes": [{"match": {"env": "prod"}}]}`,
+		"reserved name":    `{"notifiers": {"default": {"type": "slack", "url": "https://x"}}}`,
+		"clashes with env": `{"notifiers": {"env": {"type": "slack", "url": "https://x"}}}`,
+		"unknown type":     `{"notifiers": {"x": {"type": "fax"}}}`,
+		"missing settings": `{"notifiers": {"x": {"type": "pagerduty"}}}`,
+		"invalid email":    `{"notifiers": {"x": {"type": "email", "host": "smtp.x"}}}`,
+		"bad json":         `{`,
+	} {
+		path := filepath.Join(dir, "routing.json")
+		_ = os.WriteFile(path, []byte(body), 0o600)
+		if _, err := LoadRouting(path, env(&recorder{})); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+
+	path := filepath.Join(dir, "routing.json")
+	_ = os.WriteFile(path, []byte(`{"routes": [{"match": {"env": "prod"}, "notifiers": ["default"]}]}`), 0o600)
+	if _, err := LoadRouting(path, nil); err == nil {
+		t.Error("want error when default is referenced but nothing is configured")
+	}
+}
+
+func TestRouter_Escalation(t *testing.T) {
+	var cfg RoutingConfig
+	_ = json.Unmarshal([]byte(`{
+	  "notifiers": {"lead": {"type": "slack", "url": "https://x/lead"}, "manager": {"type": "slack", "url": "https://x/mgr"}},
+	  "escalations": {
+	    "payments": [{"after_minutes": 10, "notifiers": ["lead"]}, {"after_minutes": 30, "notifiers": ["manager", "lead"]}],
+	    "std": [{"after_minutes": 60, "notifiers": ["lead"]}]
+	  },
+	  "routes": [{"match": {"team": "payments"}, "notifiers": ["default"], "escalation": "payments"}],
+	  "default_escalation": "std"
+	}`), &cfg)
+	r, err := NewRouter(cfg, env(&recorder{}))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	if tiers := r.Escalation(routedEvent(KindDown, map[string]string{"team": "payments"})); len(tiers) != 2 || tiers[1].After() != 30*time.Minute {
+		t.Fatalf("payments tiers: %+v", tiers)
+	}
+	if tiers := r.Escalation(routedEvent(KindDown, nil)); len(tiers) != 1 || tiers[0].AfterMinutes != 60 {
+		t.Fatalf("default tiers: %+v", tiers)
+	}
+
+	lead, mgr := &recorder{}, &recorder{}
+	r.notifiers["lead"], r.notifiers["manager"] = lead, mgr
+	tier := r.Escalation(routedEvent(KindDown, map[string]string{"team": "payments"}))[1]
+	_ = r.NotifyTier(context.Background(), tier, Event{Kind: KindEscalated})
+	if len(lead.got) != 1 || len(mgr.got) != 1 {
+		t.Fatalf("tier delivery: lead=%d manager=%d", len(lead.got), len(mgr.got))
+	}
+
+	// A recovery reaches the tiers paged for its incident, so they resolve.
+	rec := routedEvent(KindRecovered, map[string]string{"team": "payments"})
+	if got := r.Route(rec); !reflect.DeepEqual(got, []string{"env"}) {
+		t.Fatalf("unescalated recovery: %v", got)
+	}
+	rec.Incident = &domain.Incident{Escalation: 1}
+	if got := r.Route(rec); !reflect.DeepEqual(got, []string{"env", "lead"}) {
+		t.Fatalf("recovery after tier 2: %v", got)
+	}
+	rec.Incident.Escalation = 2
+	if got := r.Route(rec); !reflect.DeepEqual(got, []string{"env", "lead", "manager"}) {
+		t.Fatalf("recovery after tier 3: %v", got)
+	}
+
+	for name, esc := range map[string]string{
+		"decreasing delay": `{"e": [{"after_minutes": 10, "notifiers": ["default"]}, {"after_minutes": 5, "notifiers": ["default"]}]}`,
+		"no notifiers":     `{"e": [{"after_minutes": 10}]}`,
+		"unknown notifier": `{"e": [{"after_minutes": 10, "notifiers": ["nope"]}]}`,
+	} {
+		var bad RoutingConfig
+		_ = json.Unmarshal([]byte(`{"escalations": `+esc+`}`), &bad)
+		if _, err := NewRouter(bad, env(&recorder{})); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+	if _, err := NewRouter(RoutingConfig{DefaultEscalation: "nope"}, env(&recorder{})); err == nil {
+		t.Error("want error for unknown default_escalation")
+	}
+}
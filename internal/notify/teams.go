@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Teams posts Adaptive Cards to a Microsoft Teams incoming webhook
+// (or a Workflows "post to a channel when a webhook request is received" URL).
+type Teams struct {
+	Webhook string
+	Client  *http.Client
+}
+
+func NewTeams(webhook string) *Teams {
+	if webhook == "" {
+		return nil
+	}
+	return &Teams{
+		Webhook: webhook,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+var teamsColors = map[string]string{
+	sevDown:    "Attention",
+	sevUp:      "Good",
+	sevWarning: "Warning",
+	sevInfo:    "Default",
+}
+
+func (t *Teams) Send(ctx context.Context, title, text string) error {
+	return t.Notify(ctx, Event{Title: title, Text: text, At: time.Now().UTC()})
+}
+
+func (t *Teams) Notify(ctx context.Context, ev Event) error {
+	return postJSON(ctx, t.Client, "teams", t.Webhook, nil, teamsMessage(ev))
+}
+
+func teamsMessage(ev Event) map[string]any {
+	facts, other := splitFacts(ev.Text)
+	body := []map[string]any{{
+		"type":   "TextBlock",
+		"text":   ev.Title,
+		"weight": "Bolder",
+		"size":   "Medium",
+		"color":  teamsColors[severityOf(ev.Kind)],
+		"wrap":   true,
+	}}
+	if len(facts) > 0 {
+		fs := make([]map[string]string, 0, len(facts))
+		for _, f := range facts {
+			fs = append(fs, map[string]string{"title": f.Name, "value": f.Value})
+		}
+		body = append(body, map[string]any{"type": "FactSet", "facts": fs})
+	}
+	for _, line := range other {
+		body = append(body, map[string]any{"type": "TextBlock", "text": line, "wrap": true})
+	}
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if link := targetLink(ev); link != "" {
+		card["actions"] = []map[string]string{{"type": "Action.OpenUrl", "title": "Open target", "url": link}}
+	}
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTeams_AdaptiveCard(t *testing.T) {
+	var got struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string              `json:"type"`
+					Text  string              `json:"text"`
+					Color string              `json:"color"`
+					Facts []map[string]string `json:"facts"`
+				} `json:"body"`
+				Actions []map[string]string `json:"actions"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	if err := NewTeams(ts.URL).Notify(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got.Type != "message" || len(got.Attachments) != 1 ||
+		got.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("unexpected envelope: %+v", got)
+	}
+	card := got.Attachments[0].Content
+	if card.Type != "AdaptiveCard" || len(card.Body) != 2 {
+		t.Fatalf("unexpected card: %+v", card)
+	}
+	if card.Body[0].Text != "🔴 Target DOWN" || card.Body[0].Color != "Attention" {
+		t.Fatalf("title block: %+v", card.Body[0])
+	}
+	facts := card.Body[1].Facts
+	if card.Body[1].Type != "FactSet" || len(facts) != 2 || facts[0]["title"] != "URL" || facts[0]["value"] != "https://a.example" {
+		t.Fatalf("facts: %+v", card.Body[1])
+	}
+	if len(card.Actions) != 1 || card.Actions[0]["url"] != "https://a.example" {
+		t.Fatalf("actions: %+v", card.Actions)
+	}
+}
+
+func TestTeams_ColorsByState(t *testing.T) {
+	for kind, want := range map[string]string{
+		KindRecovered: "Good",
+		KindDegraded:  "Warning",
+		"":            "Default",
+	} {
+		msg := teamsMessage(Event{Kind: kind, Title: "x"})
+		card := msg["attachments"].([]map[string]any)[0]["content"].(map[string]any)
+		if c := card["body"].([]map[string]any)[0]["color"]; c != want {
+			t.Errorf("%q: color %v, want %s", kind, c, want)
+		}
+	}
+}
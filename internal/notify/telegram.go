@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const telegramAPI = "https://api.telegram.org"
+
+// Telegram sends HTML-formatted messages to a chat through the Bot API.
+type Telegram struct {
+	Token  string
+	ChatID string
+	URL    string // API base
+	Client *http.Client
+}
+
+func NewTelegram(token, chatID string) *Telegram {
+	if token == "" || chatID == "" {
+		return nil
+	}
+	return &Telegram{
+		Token:  token,
+		ChatID: chatID,
+		URL:    telegramAPI,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+var telegramIcons = map[string]string{
+	sevDown:    "🔴",
+	sevUp:      "🟢",
+	sevWarning: "🟠",
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+func (t *Telegram) Send(ctx context.Context, title, text string) error {
+	return t.Notify(ctx, Event{Title: title, Text: text, At: time.Now().UTC()})
+}
+
+func (t *Telegram) Notify(ctx context.Context, ev Event) error {
+	msg := telegramMessage{
+		ChatID:                t.ChatID,
+		Text:                  telegramText(ev),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	}
+	err := postJSON(ctx, t.Client, "telegram", t.URL+"/bot"+t.Token+"/sendMessage", nil, msg)
+	// The bot token is part of the URL; keep it out of logged errors.
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return fmt.Errorf("telegram: %w", ue.Err)
+	}
+	return err
+}
+
+func telegramText(ev Event) string {
+	var b strings.Builder
+	title := ev.Title
+	// Alert titles already start with an emoji (🟡 FLAPPING, 🚨 ESCALATED,
+	// ...); add the severity's for bare ones.
+	if icon := telegramIcons[severityOf(ev.Kind)]; icon != "" && !startsWithEmoji(title) {
+		title = icon + " " + title
+	}
+	b.WriteString("<b>" + html.EscapeString(title) + "</b>")
+	facts, other := splitFacts(ev.Text)
+	for _, f := range facts {
+		// Truncate before escaping so the 4096-byte limit never cuts a tag.
+		v := html.EscapeString(truncate(f.Value, 512))
+		if f.Name == "URL" && isWebURL(f.Value) {
+			v = `<a href="` + v + `">` + v + `</a>`
+		}
+		b.WriteString("\n<b>" + html.EscapeString(f.Name) + ":</b> " + v)
+	}
+	for _, line := range other {
+		b.WriteString("\n" + html.EscapeString(truncate(line, 512)))
+	}
+	return b.String()
+}
+
+// startsWithEmoji reports whether s begins with a pictographic symbol.
+func startsWithEmoji(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r > unicode.MaxLatin1 && unicode.Is(unicode.So, r)
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTelegram_SendMessageHTML(t *testing.T) {
+	var (
+		path string
+		got  telegramMessage
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer ts.Close()
+
+	tg := NewTelegram("123:abc", "-10042")
+	tg.URL = ts.URL
+	ev := sampleEvent()
+	ev.Kind = KindDegraded
+	ev.Title = "Target DEGRADED"
+	ev.Text = "URL: https://a.example/?q=<x>&y\nLatency: p95 is 900 ms"
+	if err := tg.Notify(context.Background(), ev); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if path != "/bot123:abc/sendMessage" || got.ChatID != "-10042" || got.ParseMode != "HTML" {
+		t.Fatalf("unexpected request: %s %+v", path, got)
+	}
+	want := "<b>🟠 Target DEGRADED</b>\n" +
+		`<b>URL:</b> <a href="https://a.example/?q=&lt;x&gt;&amp;y">https://a.example/?q=&lt;x&gt;&amp;y</a>` + "\n" +
+		"<b>Latency:</b> p95 is 900 ms"
+	if got.Text != want {
+		t.Fatalf("text:\n%s\nwant:\n%s", got.Text, want)
+	}
+}
+
+func TestTelegramText_LinksOnlyWebURLs(t *testing.T) {
+	for u, want := range map[string]string{
+		"https://a.example/":       `<a href="https://a.example/">https://a.example/</a>`,
+		"tcp://db.internal:5432":   "tcp://db.internal:5432",
+		"dns://example.com?type=A": "dns://example.com?type=A",
+		`javascript:alert("x")`:    "javascript:alert(&#34;x&#34;)",
+	} {
+		got := telegramText(Event{Kind: KindDown, Title: "Target DOWN", Text: "URL: " + u})
+		if want = "<b>🔴 Target DOWN</b>\n<b>URL:</b> " + want; got != want {
+			t.Errorf("%s: got %q, want %q", u, got, want)
+		}
+	}
+}
+
+func TestTelegramText_KeepsExistingEmoji(t *testing.T) {
+	for title, want := range map[string]string{
+		"🟡 Target FLAPPING":           "<b>🟡 Target FLAPPING</b>",
+		"🚨 Outage ESCALATED (tier 2)": "<b>🚨 Outage ESCALATED (tier 2)</b>",
+		"🔴 Target DOWN":               "<b>🔴 Target DOWN</b>",
+		"Target DOWN":                 "<b>🔴 Target DOWN</b>",
+	} {
+		if got := telegramText(Event{Kind: KindDown, Title: title}); got != want {
+			t.Errorf("%s: got %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestTelegram_ErrorsHideToken(t *testing.T) {
+	tg := NewTelegram("123:secret", "1")
+	tg.URL = "http://127.0.0.1:1" // nothing listens here
+	err := tg.Send(context.Background(), "t", "x")
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("want error without token, got %v", err)
+	}
+	if NewTelegram("tok", "") != nil {
+		t.Fatal("missing chat id must disable Telegram")
+	}
+}
@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Headers set on every webhook request. The signature is
+// hex(HMAC-SHA256(secret, timestamp + "." + body)) so receivers can reject
+// forged and replayed requests.
+const (
+	WebhookSignatureHeader = "X-Uptime-Signature"
+	WebhookTimestampHeader = "X-Uptime-Timestamp"
+)
+
+// WebhookConfig describes one outgoing webhook.
+type WebhookConfig struct {
+	// Name identifies the webhook in notifier names, errors and the outbox;
+	// required, since the URL often embeds a token and is never shown.
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Template is a text/template producing the JSON body from an Event
+	// (e.g. {"text": {{json .Title}}}). Empty sends the Event as JSON.
+	Template string            `json:"template"`
+	Secret   string            `json:"secret"` // enables HMAC signing
+	Headers  map[string]string `json:"headers"`
+}
+
+// Webhook POSTs alerts to an arbitrary URL. Each Notify is a single
+// attempt; failed ones are retried with backoff by the notification outbox.
+type Webhook struct {
+	Name    string
+	URL     string
+	Secret  string
+	Headers map[string]string
+	Client  *http.Client
+
+	tmpl *template.Template
+}
+
+var webhookFuncs = template.FuncMap{
+	// json renders v as a JSON value, so strings are quoted and escaped.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func NewWebhook(cfg WebhookConfig) (*Webhook, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("webhook: name is required")
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook %s: url is required", cfg.Name)
+	}
+	w := &Webhook{
+		Name:    cfg.Name,
+		URL:     cfg.URL,
+		Secret:  cfg.Secret,
+		Headers: cfg.Headers,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	if cfg.Template != "" {
+		t, err := template.New(w.Name).Funcs(webhookFuncs).Option("missingkey=error").Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: template: %w", w.Name, err)
+		}
+		w.tmpl = t
+	}
+	return w, nil
+}
+
+// LoadWebhooks reads a JSON array of WebhookConfig from path.
+func LoadWebhooks(path string) ([]*Webhook, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read webhooks: %w", err)
+	}
+	var cfgs []WebhookConfig
+	if err := json.Unmarshal(raw, &cfgs); err != nil {
+		return nil, fmt.Errorf("parse webhooks: %w", err)
+	}
+	out := make([]*Webhook, 0, len(cfgs))
+	for _, c := range cfgs {
+		w, err := NewWebhook(c)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+// Send posts a bare title/text event.
+func (w *Webhook) Send(ctx context.Context, title, text string) error {
+	return w.Notify(ctx, Event{Title: title, Text: text, At: time.Now().UTC()})
+}
+
+func (w *Webhook) Notify(ctx context.Context, ev Event) error {
+	body, err := w.render(ev)
+	if err != nil {
+		return err
+	}
+	return w.post(ctx, body)
+}
+
+func (w *Webhook) render(ev Event) ([]byte, error) {
+	if w.tmpl == nil {
+		return json.Marshal(ev)
+	}
+	var buf bytes.Buffer
+	if err := w.tmpl.Execute(&buf, ev); err != nil {
+		return nil, fmt.Errorf("webhook %s: render: %w", w.Name, err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook %s: template did not produce valid JSON", w.Name)
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "uptimechecker-webhook")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, ts)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+Sign(w.Secret, ts, body))
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s: status %d", w.Name, resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of timestamp + "." + body, as sent in
+// WebhookSignatureHeader (after the "sha256=" prefix).
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a WebhookSignatureHeader value; for receivers and tests.
+func VerifySignature(secret, timestamp, header string, body []byte) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(Sign(secret, timestamp, body)))
+}
@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+func sampleEvent() Event {
+	code := 503
+	return Event{
+		Kind:          KindDown,
+		Title:         "🔴 Target DOWN",
+		Text:          "URL: https://a.example\nReason: \"503\"",
+		Target:        &domain.Target{ID: "t1", URL: "https://a.example"},
+		Result:        &domain.Result{TargetID: "t1", Up: false, HTTPStatus: &code, Reason: "503"},
+		PreviousState: "up",
+		Incident:      &domain.Incident{ID: 7, TargetID: "t1"},
+	}
+}
+
+func TestWebhook_TemplateSignatureAndHeaders(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header.Clone()
+	}))
+	defer ts.Close()
+
+	w, err := NewWebhook(WebhookConfig{
+		Name:     "bot",
+		URL:      ts.URL,
+		Template: `{"summary": {{json .Title}}, "target": {{json .Target.ID}}, "status": {{.Result.HTTPStatus}}, "was": {{json .PreviousState}}, "incident": {{.Incident.ID}}, "text": {{json .Text}}}`,
+		Secret:   "s3cret",
+		Headers:  map[string]string{"Authorization": "Bearer tok"},
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	if err := Deliver(context.Background(), w, sampleEvent()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("body is not JSON: %v\n%s", err, body)
+	}
+	if got["summary"] != "🔴 Target DOWN" || got["target"] != "t1" || got["status"] != float64(503) ||
+		got["was"] != "up" || got["incident"] != float64(7) || got["text"] != "URL: https://a.example\nReason: \"503\"" {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+	if headers.Get("Authorization") != "Bearer tok" || headers.Get("Content-Type") != "application/json" {
+		t.Fatalf("headers: %v", headers)
+	}
+	ts1 := headers.Get(WebhookTimestampHeader)
+	if !VerifySignature("s3cret", ts1, headers.Get(WebhookSignatureHeader), body) {
+		t.Fatalf("bad signature %q", headers.Get(WebhookSignatureHeader))
+	}
+	if VerifySignature("other", ts1, headers.Get(WebhookSignatureHeader), body) {
+		t.Fatal("signature must depend on the secret")
+	}
+}
+
+func TestWebhook_DefaultBodyIsEvent(t *testing.T) {
+	var got Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer ts.Close()
+
+	w, _ := NewWebhook(WebhookConfig{Name: "hook", URL: ts.URL})
+	if err := w.Notify(context.Background(), sampleEvent()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got.Kind != KindDown || got.Target == nil || got.Target.ID != "t1" || got.Incident == nil {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+}
+
+func TestWebhook_SingleAttempt(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	// retries are left to the outbox, which backs off between attempts
+	w, _ := NewWebhook(WebhookConfig{Name: "hook", URL: ts.URL})
+	if err := w.Send(context.Background(), "t", "x"); err == nil {
+		t.Fatal("want error for 503")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("want 1 attempt, got %d", n)
+	}
+}
+
+func TestWebhook_InvalidTemplate(t *testing.T) {
+	if _, err := NewWebhook(WebhookConfig{Name: "hook", URL: "http://x", Template: "{{"}); err == nil {
+		t.Fatal("want parse error")
+	}
+	w, _ := NewWebhook(WebhookConfig{Name: "hook", URL: "http://x", Template: `{"text": {{.Title}}}`})
+	if err := w.Notify(context.Background(), Event{Title: "not json", At: time.Now()}); err == nil {
+		t.Fatal("want error for non-JSON output")
+	}
+}
+
+func TestLoadWebhooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	_ = os.WriteFile(path, []byte(`[{"name":"bot","url":"https://bot.example/hook","headers":{"X-Team":"sre"}}]`), 0o600)
+	ws, err := LoadWebhooks(path)
+	if err != nil || len(ws) != 1 {
+		t.Fatalf("LoadWebhooks: %v %v", ws, err)
+	}
+	if ws[0].Name != "bot" || ws[0].URL != "https://bot.example/hook" || ws[0].Headers["X-Team"] != "sre" {
+		t.Fatalf("unexpected webhook: %+v", ws[0])
+	}
+	_ = os.WriteFile(path, []byte(`[{"name":"no url"}]`), 0o600)
+	if _, err := LoadWebhooks(path); err == nil {
+		t.Fatal("want error for missing url")
+	}
+	// the URL may hold a token, so it is never used as the name
+	_ = os.WriteFile(path, []byte(`[{"url":"https://hooks.example/T0/secret"}]`), 0o600)
+	if _, err := LoadWebhooks(path); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("want error for missing name without the URL, got %v", err)
+	}
+}
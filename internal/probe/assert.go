@@ -0,0 +1,68 @@
+package probe
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+// maxCachedRegexps bounds bodyRegexps; edited patterns would otherwise pile up.
+const maxCachedRegexps = 1024
+
+// bodyRegexps caches compiled body_regex patterns, so a target's pattern is
+// compiled once rather than on every check.
+var bodyRegexps = struct {
+	sync.Mutex
+	m map[string]*regexp.Regexp
+}{m: make(map[string]*regexp.Regexp)}
+
+// bodyRegexp returns the compiled pattern, compiling it on first use.
+func bodyRegexp(pattern string) (*regexp.Regexp, error) {
+	bodyRegexps.Lock()
+	defer bodyRegexps.Unlock()
+	if re, ok := bodyRegexps.m[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(bodyRegexps.m) >= maxCachedRegexps {
+		clear(bodyRegexps.m)
+	}
+	bodyRegexps.m[pattern] = re
+	return re, nil
+}
+
+// hasBodyAssertions reports whether o requires the response body to be read.
+func hasBodyAssertions(o *domain.HTTPOptions) bool {
+	return o != nil && (o.BodyContains != "" || o.BodyNotContains != "" || o.BodyRegex != "" || len(o.JSON) > 0)
+}
+
+// checkBody evaluates the body assertions in o and returns a short reason for
+// the first one that fails, or "" if they all hold.
+func checkBody(o *domain.HTTPOptions, body []byte) string {
+	s := string(body)
+	if o.BodyContains != "" && !strings.Contains(s, o.BodyContains) {
+		return fmt.Sprintf("assertion failed: body does not contain %q", o.BodyContains)
+	}
+	if o.BodyNotContains != "" && strings.Contains(s, o.BodyNotContains) {
+		return fmt.Sprintf("assertion failed: body contains %q", o.BodyNotContains)
+	}
+	if o.BodyRegex != "" {
+		re, err := bodyRegexp(o.BodyRegex)
+		if err != nil {
+			return "assertion failed: bad regex: " + err.Error()
+		}
+		if !re.Match(body) {
+			return fmt.Sprintf("assertion failed: body does not match /%s/", o.BodyRegex)
+		}
+	}
+	if len(o.JSON) > 0 {
+		return checkJSON(o.JSON, body)
+	}
+	return ""
+}
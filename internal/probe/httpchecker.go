@@ -3,40 +3,71 @@ package probe
 import (
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
 )
 
+// defaultMaxBodyBytes caps how much body is read when a target has body assertions.
+const defaultMaxBodyBytes = 64 << 10
+
 // httpChecker implements Checker with a plain http.Client.
 type httpChecker struct {
-	client *http.Client
+	client  *http.Client
+	timeout time.Duration // used when the context has no deadline
+	maxBody int64
+}
+
+// HTTPOption customizes the checker returned by NewHTTPChecker.
+type HTTPOption func(*httpChecker)
+
+// WithMaxBodyBytes sets how much of the response body is read for assertions.
+// Targets can still override it via HTTPOptions.MaxBodyBytes.
+func WithMaxBodyBytes(n int64) HTTPOption {
+	return func(h *httpChecker) {
+		if n > 0 {
+			h.maxBody = n
+		}
+	}
 }
 
-// NewHTTPChecker returns a Checker that does a single HTTP GET with the given timeout.
-func NewHTTPChecker(timeout time.Duration) Checker {
+// NewHTTPChecker returns a Checker that does a single HTTP GET. timeout
+// applies when the context has no deadline of its own, so a per-target
+// timeout set by the caller can be longer or shorter. Per-target options
+// attached with WithHTTPOptions are honoured.
+func NewHTTPChecker(timeout time.Duration, opts ...HTTPOption) Checker {
 	if timeout <= 0 {
 		timeout = 5 * time.Second
 	}
 	tr := &http.Transport{
-		Proxy:               http.ProxyFromEnvironment,
-		MaxIdleConns:        100,
-		IdleConnTimeout:     90 * time.Second,
-		TLSHandshakeTimeout: 10 * time.Second,
-		TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS12},
-	}
-	return &httpChecker{
-		client: &http.Client{
-			Timeout:   timeout,
-			Transport: tr,
-		},
+		Proxy:           http.ProxyFromEnvironment,
+		MaxIdleConns:    100,
+		IdleConnTimeout: 90 * time.Second,
+		TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	h := &httpChecker{
+		client:  &http.Client{Transport: tr},
+		timeout: timeout,
+		maxBody: defaultMaxBodyBytes,
+	}
+	for _, o := range opts {
+		o(h)
 	}
+	return h
 }
 
 func (h *httpChecker) Check(ctx context.Context, target string) CheckResult {
+	ctx, cancel := withDefaultTimeout(ctx, h.timeout)
+	defer cancel()
 	start := time.Now()
+	opts := httpOptionsFrom(ctx)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	req, err := newRequest(ctx, target, opts)
 	if err != nil {
 		return CheckResult{
 			Success:    false,
@@ -45,7 +76,6 @@ func (h *httpChecker) Check(ctx context.Context, target string) CheckResult {
 			StatusCode: 0,
 		}
 	}
-	req.Header.Set("User-Agent", "uptimechecker/1.0")
 
 	resp, err := h.client.Do(req)
 	if err != nil {
@@ -57,19 +87,131 @@ func (h *httpChecker) Check(ctx context.Context, target string) CheckResult {
 		}
 	}
 	defer resp.Body.Close()
-	_, _ = io.CopyN(io.Discard, resp.Body, 512) // let keep-alive work
+
+	var body []byte
+	if hasBodyAssertions(opts) {
+		limit := h.maxBody
+		if opts.MaxBodyBytes > 0 {
+			limit = opts.MaxBodyBytes
+		}
+		body, err = io.ReadAll(io.LimitReader(resp.Body, limit))
+		if err != nil {
+			return CheckResult{
+				Success:    false,
+				LatencyMS:  msSince(start),
+				Message:    resp.Status + "; read body: " + err.Error(),
+				StatusCode: resp.StatusCode,
+			}
+		}
+	} else {
+		_, _ = io.CopyN(io.Discard, resp.Body, 512) // let keep-alive work
+	}
 
 	lat := msSince(start)
-	ok := resp.StatusCode >= 200 && resp.StatusCode <= 399
+	ok := statusAccepted(resp.StatusCode, opts)
+	msg := resp.Status // e.g. "200 OK"
+
+	if ok && hasBodyAssertions(opts) {
+		if reason := checkBody(opts, body); reason != "" {
+			ok = false
+			msg = msg + "; " + reason
+		}
+	}
 
 	return CheckResult{
 		Success:    ok,
 		LatencyMS:  lat,
-		Message:    resp.Status, // e.g. "200 OK"
+		Message:    msg,
 		StatusCode: resp.StatusCode,
 	}
 }
 
+// newRequest builds the probe request from the target URL and its options.
+func newRequest(ctx context.Context, target string, o *domain.HTTPOptions) (*http.Request, error) {
+	method := http.MethodGet
+	var body io.Reader
+	if o != nil {
+		if o.Method != "" {
+			method = strings.ToUpper(o.Method)
+		}
+		if o.Body != "" {
+			body = strings.NewReader(o.Body)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "uptimechecker/1.0")
+	if o == nil {
+		return req, nil
+	}
+	for k, v := range o.Headers {
+		req.Header.Set(k, v)
+	}
+	if o.BasicAuth != nil {
+		req.SetBasicAuth(o.BasicAuth.Username, o.BasicAuth.Password)
+	}
+	if o.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+o.BearerToken)
+	}
+	return req, nil
+}
+
+// statusAccepted reports whether code counts as up: any 2xx/3xx by default,
+// or exactly the codes listed in AcceptStatus.
+func statusAccepted(code int, o *domain.HTTPOptions) bool {
+	if o == nil || len(o.AcceptStatus) == 0 {
+		return code >= 200 && code <= 399
+	}
+	for _, c := range o.AcceptStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 func msSince(t time.Time) float64 {
 	return float64(time.Since(t).Milliseconds())
 }
+
+// ValidateHTTPOptions reports option values the checker could not use.
+func ValidateHTTPOptions(o *domain.HTTPOptions) error {
+	if o == nil {
+		return nil
+	}
+	switch strings.ToUpper(o.Method) {
+	case "", http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		return fmt.Errorf("method %q not supported", o.Method)
+	}
+	if o.BasicAuth != nil && o.BearerToken != "" {
+		return errors.New("use either basic_auth or bearer_token, not both")
+	}
+	for k := range o.Headers {
+		if strings.TrimSpace(k) == "" || strings.ContainsAny(k, " :\r\n") {
+			return fmt.Errorf("header name %q is invalid", k)
+		}
+	}
+	for _, c := range o.AcceptStatus {
+		if c < 100 || c > 599 {
+			return fmt.Errorf("accept_status %d out of range", c)
+		}
+	}
+	if o.BodyRegex != "" {
+		if _, err := bodyRegexp(o.BodyRegex); err != nil {
+			return fmt.Errorf("body_regex: %w", err)
+		}
+	}
+	for _, e := range o.JSON {
+		if _, err := parseJSONAssertion(e); err != nil {
+			return fmt.Errorf("json %q: %w", e, err)
+		}
+	}
+	if o.MaxBodyBytes < 0 {
+		return errors.New("max_body_bytes must be >= 0")
+	}
+	return nil
+}
@@ -2,11 +2,14 @@ package probe
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
 )
 
 func TestHTTPChecker_StatusOK(t *testing.T) {
@@ -71,3 +74,141 @@ func TestHTTPChecker_TimeoutSetsStatusZero(t *testing.T) {
 		t.Fatalf("want non-empty error message")
 	}
 }
+
+func TestHTTPChecker_ContextDeadlineOverridesDefault(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(200)
+	}))
+	defer s.Close()
+
+	// A per-target timeout longer than the checker default is honoured.
+	chk := NewHTTPChecker(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if out := chk.Check(ctx, s.URL); !out.Success {
+		t.Fatalf("want success within the context deadline, got %+v", out)
+	}
+}
+
+func TestHTTPChecker_BodyAssertions(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write([]byte("<h1>Down for maintenance</h1>"))
+	}))
+	defer s.Close()
+
+	chk := NewHTTPChecker(2 * time.Second)
+	cases := []struct {
+		name string
+		opts domain.HTTPOptions
+		ok   bool
+	}{
+		{"contains ok", domain.HTTPOptions{BodyContains: "<h1>"}, true},
+		{"contains missing", domain.HTTPOptions{BodyContains: "Welcome"}, false},
+		{"not contains hit", domain.HTTPOptions{BodyNotContains: "maintenance"}, false},
+		{"regex match", domain.HTTPOptions{BodyRegex: `(?i)down\s+for`}, true},
+		{"regex miss", domain.HTTPOptions{BodyRegex: `^OK$`}, false},
+		{"limit hides match", domain.HTTPOptions{BodyContains: "maintenance", MaxBodyBytes: 8}, false},
+	}
+	for _, c := range cases {
+		o := c.opts
+		out := chk.Check(WithHTTPOptions(context.Background(), &o), s.URL)
+		if out.Success != c.ok {
+			t.Fatalf("%s: want success=%v, got %+v", c.name, c.ok, out)
+		}
+		if !c.ok && !strings.Contains(out.Message, "assertion failed") {
+			t.Fatalf("%s: want assertion reason, got %q", c.name, out.Message)
+		}
+		if out.StatusCode != 200 {
+			t.Fatalf("%s: want status 200 kept, got %d", c.name, out.StatusCode)
+		}
+	}
+}
+
+func TestValidateHTTPOptions(t *testing.T) {
+	if err := ValidateHTTPOptions(&domain.HTTPOptions{BodyRegex: "("}); err == nil {
+		t.Fatalf("want error for bad regex")
+	}
+	if err := ValidateHTTPOptions(&domain.HTTPOptions{Method: "TRACE"}); err == nil {
+		t.Fatalf("want error for unsupported method")
+	}
+	if err := ValidateHTTPOptions(&domain.HTTPOptions{BasicAuth: &domain.BasicAuth{}, BearerToken: "x"}); err == nil {
+		t.Fatalf("want error for two auth modes")
+	}
+	if err := ValidateHTTPOptions(&domain.HTTPOptions{AcceptStatus: []int{999}}); err == nil {
+		t.Fatalf("want error for bad status")
+	}
+	if err := ValidateHTTPOptions(&domain.HTTPOptions{BodyRegex: "ok"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBodyRegexp_CompilesOnce(t *testing.T) {
+	a, err := bodyRegexp(`(?i)healthy\s+\d+`)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if b, _ := bodyRegexp(`(?i)healthy\s+\d+`); b != a {
+		t.Fatalf("want the cached regexp for a repeated pattern")
+	}
+	if _, err := bodyRegexp("("); err == nil {
+		t.Fatalf("want error for bad regex")
+	}
+}
+
+func TestHTTPChecker_JSONAssertion(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok","checks":{"db":{"healthy":false}}}`))
+	}))
+	defer s.Close()
+
+	opts := &domain.HTTPOptions{JSON: []string{`$.status == "ok"`, `$.checks.db.healthy == true`}}
+	out := NewHTTPChecker(2*time.Second).Check(WithHTTPOptions(context.Background(), opts), s.URL)
+	if out.Success {
+		t.Fatalf("want failure, got %+v", out)
+	}
+	if !strings.Contains(out.Message, "$.checks.db.healthy") || !strings.Contains(out.Message, "actual: false") {
+		t.Fatalf("want failed path and actual value in message, got %q", out.Message)
+	}
+}
+
+func TestHTTPChecker_RequestOptions(t *testing.T) {
+	var gotMethod, gotBody, gotAuth, gotHeader string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		gotAuth = r.Header.Get("Authorization")
+		gotHeader = r.Header.Get("X-Probe")
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer s.Close()
+
+	chk := NewHTTPChecker(2 * time.Second)
+	opts := &domain.HTTPOptions{
+		Method:       "post",
+		Headers:      map[string]string{"X-Probe": "1"},
+		Body:         `{"ping":true}`,
+		BearerToken:  "tok",
+		AcceptStatus: []int{401},
+	}
+	out := chk.Check(WithHTTPOptions(context.Background(), opts), s.URL)
+	if !out.Success {
+		t.Fatalf("want 401 accepted, got %+v", out)
+	}
+	if gotMethod != http.MethodPost || gotBody != `{"ping":true}` || gotAuth != "Bearer tok" || gotHeader != "1" {
+		t.Fatalf("request not built from options: method=%s body=%s auth=%s hdr=%s", gotMethod, gotBody, gotAuth, gotHeader)
+	}
+
+	// 401 is down once it's no longer listed; basic auth is sent
+	opts = &domain.HTTPOptions{BasicAuth: &domain.BasicAuth{Username: "u", Password: "p"}, AcceptStatus: []int{200}}
+	out = chk.Check(WithHTTPOptions(context.Background(), opts), s.URL)
+	if out.Success {
+		t.Fatalf("want failure for unlisted status, got %+v", out)
+	}
+	if !strings.HasPrefix(gotAuth, "Basic ") {
+		t.Fatalf("want basic auth header, got %q", gotAuth)
+	}
+}
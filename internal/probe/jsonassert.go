@@ -0,0 +1,164 @@
+package probe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonAssertion is a parsed expression such as `$.checks.db.healthy == true`.
+// Supported forms:
+//
+//	$.path == <json literal>
+//	$.path != <json literal>
+//	$.path                    (path must exist)
+//
+// Paths use dot notation with optional array indexes, e.g. $.items[0].name.
+type jsonAssertion struct {
+	expr string
+	path []any // string keys and int indexes
+	op   string
+	want any
+}
+
+func parseJSONAssertion(expr string) (jsonAssertion, error) {
+	a := jsonAssertion{expr: strings.TrimSpace(expr)}
+	// The path is read first so an operator inside the value (e.g.
+	// `$.a != "x==y"`) is not mistaken for the comparison.
+	path, rest, err := parseJSONPath(a.expr)
+	if err != nil {
+		return a, err
+	}
+	a.path = path
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return a, nil
+	}
+	if op := rest[:min(2, len(rest))]; op == "==" || op == "!=" {
+		a.op = op
+	} else {
+		return a, fmt.Errorf("unexpected %q after path in %q", rest, a.expr)
+	}
+	rhs := strings.TrimSpace(rest[2:])
+	if err := json.Unmarshal([]byte(rhs), &a.want); err != nil {
+		return a, fmt.Errorf("bad value %q: %w", rhs, err)
+	}
+	return a, nil
+}
+
+// parseJSONPath reads the path at the start of p and returns it along with
+// the rest of p. Keys end at '.', '[', whitespace or an operator.
+func parseJSONPath(p string) ([]any, string, error) {
+	if !strings.HasPrefix(p, "$") {
+		return nil, "", errors.New("path must start with $")
+	}
+	var out []any
+	rest := p[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[ \t=!")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, "", fmt.Errorf("empty key in %q", p)
+			}
+			out = append(out, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, "", fmt.Errorf("unclosed [ in %q", p)
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil || i < 0 {
+				return nil, "", fmt.Errorf("bad index in %q", p)
+			}
+			out = append(out, i)
+			rest = rest[end+1:]
+		default:
+			return out, rest, nil
+		}
+	}
+	return out, "", nil
+}
+
+// lookup walks doc along a.path and returns the value found there.
+func (a jsonAssertion) lookup(doc any) (any, bool) {
+	cur := doc
+	for _, seg := range a.path {
+		switch k := seg.(type) {
+		case string:
+			m, ok := cur.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if cur, ok = m[k]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := cur.([]any)
+			if !ok || k >= len(arr) {
+				return nil, false
+			}
+			cur = arr[k]
+		}
+	}
+	return cur, true
+}
+
+// eval returns "" when the assertion holds, otherwise a reason naming the
+// expression and the value actually found.
+func (a jsonAssertion) eval(doc any) string {
+	got, found := a.lookup(doc)
+	if !found {
+		return fmt.Sprintf("json assertion failed: %s (path not found)", a.expr)
+	}
+	switch a.op {
+	case "==":
+		if !reflect.DeepEqual(got, a.want) {
+			return fmt.Sprintf("json assertion failed: %s (actual: %s)", a.expr, jsonText(got))
+		}
+	case "!=":
+		if reflect.DeepEqual(got, a.want) {
+			return fmt.Sprintf("json assertion failed: %s (actual: %s)", a.expr, jsonText(got))
+		}
+	}
+	return ""
+}
+
+// checkJSON evaluates every expression against body and returns the reason
+// for the first failure, or "" if all hold.
+func checkJSON(exprs []string, body []byte) string {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "json assertion failed: body is not valid JSON"
+	}
+	for _, e := range exprs {
+		a, err := parseJSONAssertion(e)
+		if err != nil {
+			return "json assertion failed: " + err.Error()
+		}
+		if reason := a.eval(doc); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+func jsonText(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	const max = 120
+	if len(b) > max {
+		return string(b[:max]) + "…"
+	}
+	return string(b)
+}
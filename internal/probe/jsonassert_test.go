@@ -0,0 +1,52 @@
+package probe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckJSON(t *testing.T) {
+	body := []byte(`{"status":"degraded","expr":"x==y","checks":{"db":{"healthy":true},"cache":{"healthy":false}},"nodes":[{"id":"a"},{"id":"b"}],"version":3}`)
+	cases := []struct {
+		expr string
+		fail string // substring of reason; "" means the assertion holds
+	}{
+		{`$.checks.db.healthy == true`, ""},
+		{`$.nodes[1].id == "b"`, ""},
+		{`$.version == 3`, ""},
+		{`$.status != "ok"`, ""},
+		{`$.checks.db`, ""},
+		{`$.expr == "x==y"`, ""},
+		{`$.status != "x==y"`, ""},
+		{`$.expr!="a!=b"`, ""},
+		{`$.expr != "x==y"`, `(actual: "x==y")`},
+		{`$.status == "ok"`, `(actual: "degraded")`},
+		{`$.checks.cache.healthy == true`, `(actual: false)`},
+		{`$.checks.queue.healthy == true`, "path not found"},
+		{`$.nodes[5].id == "x"`, "path not found"},
+	}
+	for _, c := range cases {
+		got := checkJSON([]string{c.expr}, body)
+		if c.fail == "" && got != "" {
+			t.Fatalf("%s: want pass, got %q", c.expr, got)
+		}
+		if c.fail != "" && !strings.Contains(got, c.fail) {
+			t.Fatalf("%s: want reason containing %q, got %q", c.expr, c.fail, got)
+		}
+		if c.fail != "" && !strings.Contains(got, c.expr) {
+			t.Fatalf("%s: reason should name the expression, got %q", c.expr, got)
+		}
+	}
+
+	if got := checkJSON([]string{`$.status == "ok"`}, []byte("<html>")); !strings.Contains(got, "not valid JSON") {
+		t.Fatalf("want invalid JSON reason, got %q", got)
+	}
+}
+
+func TestParseJSONAssertion_Errors(t *testing.T) {
+	for _, e := range []string{`status == "ok"`, `$.a == ok`, `$.a[x] == 1`, `$..a`, `$.a = 1`, `$.a "ok"`} {
+		if _, err := parseJSONAssertion(e); err == nil {
+			t.Fatalf("%s: want parse error", e)
+		}
+	}
+}
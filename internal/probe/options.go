@@ -0,0 +1,24 @@
+package probe
+
+import (
+	"context"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+type httpOptionsKey struct{}
+
+// WithHTTPOptions attaches per-target HTTP options to ctx. Checkers that
+// understand them (the HTTP checker) pick them up; others ignore them, so
+// wrappers like RetryChecker and SchemeChecker pass them through untouched.
+func WithHTTPOptions(ctx context.Context, o *domain.HTTPOptions) context.Context {
+	if o == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, httpOptionsKey{}, o)
+}
+
+func httpOptionsFrom(ctx context.Context) *domain.HTTPOptions {
+	o, _ := ctx.Value(httpOptionsKey{}).(*domain.HTTPOptions)
+	return o
+}
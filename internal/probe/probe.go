@@ -1,6 +1,11 @@
 package probe
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
 
 // CheckResult is the unified result of a single probe.
 //
@@ -8,15 +13,29 @@ import "context"
 //   - StatusCode: HTTP status code when available; 0 for transport/DNS errors.
 //   - Name: optional label some checkers may use (e.g., DNS record type). It's harmless
 //     to keep here so existing code like dnschecker.go can set it.
+//   - Degraded: the target answered but something needs attention (e.g., the
+//     certificate expires soon). Only meaningful when Success is true.
+//   - TLS: leaf certificate details when a TLS handshake took place.
 type CheckResult struct {
 	Success    bool
 	LatencyMS  float64
 	Message    string
 	StatusCode int
 	Name       string
+	Degraded   bool
+	TLS        *domain.CertInfo
 }
 
 // Checker performs a single check for a given target URL.
 type Checker interface {
 	Check(ctx context.Context, target string) CheckResult
 }
+
+// withDefaultTimeout bounds ctx by d unless the caller already set a
+// deadline (e.g. a per-target timeout), which then applies as is.
+func withDefaultTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
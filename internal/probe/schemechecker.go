@@ -0,0 +1,29 @@
+package probe
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// SchemeChecker dispatches each target to the Checker registered for its URL
+// scheme, so one Checker can serve http(s):// and tcp:// targets alike.
+type SchemeChecker struct {
+	Checkers map[string]Checker
+}
+
+func NewSchemeChecker(checkers map[string]Checker) *SchemeChecker {
+	return &SchemeChecker{Checkers: checkers}
+}
+
+func (s *SchemeChecker) Check(ctx context.Context, target string) CheckResult {
+	u, err := url.Parse(target)
+	if err != nil {
+		return CheckResult{Success: false, Message: err.Error()}
+	}
+	c, ok := s.Checkers[strings.ToLower(u.Scheme)]
+	if !ok || c == nil {
+		return CheckResult{Success: false, Message: "unsupported scheme " + u.Scheme}
+	}
+	return c.Check(ctx, target)
+}
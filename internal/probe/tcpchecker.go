@@ -0,0 +1,98 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"time"
+)
+
+// tcpChecker implements Checker by opening a TCP connection to host:port.
+type tcpChecker struct {
+	dialer  *net.Dialer
+	timeout time.Duration // used when the context has no deadline
+}
+
+// NewTCPChecker returns a Checker for tcp://host:port targets. A check
+// succeeds when the connection is established before the context deadline,
+// or within timeout if there is none.
+func NewTCPChecker(timeout time.Duration) Checker {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &tcpChecker{dialer: &net.Dialer{}, timeout: timeout}
+}
+
+func (c *tcpChecker) Check(ctx context.Context, target string) CheckResult {
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+
+	addr, err := tcpAddr(target)
+	if err != nil {
+		return CheckResult{
+			Name:      "TCP",
+			Success:   false,
+			LatencyMS: msSince(start),
+			Message:   err.Error(),
+		}
+	}
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return CheckResult{
+			Name:      "TCP",
+			Success:   false,
+			LatencyMS: msSince(start),
+			Message:   classifyDialError(err) + ": " + err.Error(),
+		}
+	}
+	lat := msSince(start)
+	_ = conn.Close()
+
+	return CheckResult{
+		Name:      "TCP",
+		Success:   true,
+		LatencyMS: lat,
+		Message:   "connected",
+	}
+}
+
+// tcpAddr extracts host:port from a tcp:// target.
+func tcpAddr(target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "tcp" {
+		return "", errors.New("unsupported scheme " + u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return "", errors.New("tcp target must be host:port")
+	}
+	return u.Host, nil
+}
+
+// classifyDialError maps a dial error to a short failure class:
+// "refused", "timeout", "unreachable", "dns" or "error".
+func classifyDialError(err error) string {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "refused"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return "unreachable"
+	case errors.As(err, &dnsErr):
+		return "dns"
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return "timeout"
+	}
+	return "error"
+}
@@ -0,0 +1,76 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTCPChecker_Connects(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+
+	chk := NewTCPChecker(2 * time.Second)
+	out := chk.Check(context.Background(), "tcp://"+ln.Addr().String())
+	if !out.Success {
+		t.Fatalf("want success, got %+v", out)
+	}
+	if out.StatusCode != 0 {
+		t.Fatalf("want status 0 for tcp, got %d", out.StatusCode)
+	}
+	if out.LatencyMS < 0 {
+		t.Fatalf("latency should be >= 0, got %f", out.LatencyMS)
+	}
+}
+
+func TestTCPChecker_Refused(t *testing.T) {
+	// grab a free port, then close it so nothing is listening
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	chk := NewTCPChecker(2 * time.Second)
+	out := chk.Check(context.Background(), "tcp://"+addr)
+	if out.Success {
+		t.Fatalf("want failure, got %+v", out)
+	}
+	if !strings.HasPrefix(out.Message, "refused") {
+		t.Fatalf("want refused reason, got %q", out.Message)
+	}
+}
+
+func TestTCPChecker_RequiresPort(t *testing.T) {
+	out := NewTCPChecker(time.Second).Check(context.Background(), "tcp://db.internal")
+	if out.Success || out.Message == "" {
+		t.Fatalf("want failure with message, got %+v", out)
+	}
+}
+
+func TestSchemeChecker_Dispatch(t *testing.T) {
+	ok := &fakeChecker{results: []CheckResult{{Success: true, Message: "tcp"}}}
+	sc := NewSchemeChecker(map[string]Checker{"tcp": ok})
+
+	if out := sc.Check(context.Background(), "tcp://x:1"); !out.Success || out.Message != "tcp" {
+		t.Fatalf("want tcp checker result, got %+v", out)
+	}
+	if out := sc.Check(context.Background(), "ftp://x"); out.Success {
+		t.Fatalf("want failure for unknown scheme, got %+v", out)
+	}
+}
@@ -0,0 +1,169 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+// tlsChecker implements Checker by doing a TLS handshake and inspecting the
+// leaf certificate. Verification is done by hand so an invalid chain is
+// reported (with its details) instead of just failing the handshake.
+type tlsChecker struct {
+	dialer  *net.Dialer
+	timeout time.Duration // used when the context has no deadline
+	window  time.Duration
+	roots   *x509.CertPool // nil => system roots
+}
+
+// NewTLSChecker returns a Checker for https:// targets. The result is marked
+// Degraded when the certificate expires within window. timeout applies when
+// the context has no deadline.
+func NewTLSChecker(timeout, window time.Duration) Checker {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &tlsChecker{
+		dialer:  &net.Dialer{},
+		timeout: timeout,
+		window:  window,
+	}
+}
+
+func (c *tlsChecker) Check(ctx context.Context, target string) CheckResult {
+	ctx, cancel := withDefaultTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+
+	host, addr, err := tlsAddr(target)
+	if err != nil {
+		return CheckResult{Name: "TLS", LatencyMS: msSince(start), Message: err.Error()}
+	}
+
+	d := &tls.Dialer{
+		NetDialer: c.dialer,
+		Config: &tls.Config{
+			ServerName:         host,
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: true, // verified below so we can record why
+		},
+	}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return CheckResult{Name: "TLS", LatencyMS: msSince(start), Message: "tls handshake: " + err.Error()}
+	}
+	lat := msSince(start)
+	state := conn.(*tls.Conn).ConnectionState()
+	_ = conn.Close()
+
+	if len(state.PeerCertificates) == 0 {
+		return CheckResult{Name: "TLS", LatencyMS: lat, Message: "no peer certificate"}
+	}
+	leaf := state.PeerCertificates[0]
+	inter := x509.NewCertPool()
+	for _, ic := range state.PeerCertificates[1:] {
+		inter.AddCert(ic)
+	}
+
+	info := &domain.CertInfo{
+		NotAfter:   leaf.NotAfter.UTC(),
+		Issuer:     leaf.Issuer.String(),
+		SANs:       certSANs(leaf),
+		ChainValid: true,
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         c.roots,
+		Intermediates: inter,
+	}); err != nil {
+		info.ChainValid = false
+		info.ChainError = err.Error()
+	}
+
+	left := time.Until(leaf.NotAfter)
+	out := CheckResult{Name: "TLS", LatencyMS: lat, TLS: info}
+	switch {
+	case left <= 0:
+		out.Message = "certificate expired on " + leaf.NotAfter.UTC().Format(time.DateOnly)
+	case !info.ChainValid:
+		out.Message = "certificate invalid: " + info.ChainError
+	case left < c.window:
+		info.Expiring = true
+		out.Success = true
+		out.Degraded = true
+		out.Message = fmt.Sprintf("certificate expires in %d days", daysLeft(left))
+	default:
+		out.Success = true
+		out.Message = fmt.Sprintf("certificate valid for %d days", daysLeft(left))
+	}
+	return out
+}
+
+// WithTLS runs inner and, for https:// targets, a TLS certificate check too.
+// A bad certificate turns a successful result into a failure; an expiring one
+// marks it Degraded.
+func WithTLS(inner, tlsChk Checker) Checker {
+	return &tlsAugmented{inner: inner, tls: tlsChk}
+}
+
+type tlsAugmented struct {
+	inner Checker
+	tls   Checker
+}
+
+func (a *tlsAugmented) Check(ctx context.Context, target string) CheckResult {
+	out := a.inner.Check(ctx, target)
+	if u, err := url.Parse(target); err != nil || u.Scheme != "https" {
+		return out
+	}
+	t := a.tls.Check(ctx, target)
+	out.TLS = t.TLS
+	if out.Success && !t.Success {
+		out.Success = false
+		out.Message = t.Message
+	}
+	if t.Degraded {
+		out.Degraded = true
+		out.Message = out.Message + "; " + t.Message
+	}
+	return out
+}
+
+// tlsAddr returns the SNI host and dial address for an https:// target.
+func tlsAddr(target string) (string, string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme != "https" {
+		return "", "", errors.New("unsupported scheme " + u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return "", "", errors.New("missing host")
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return host, net.JoinHostPort(host, port), nil
+}
+
+func certSANs(c *x509.Certificate) []string {
+	out := append([]string(nil), c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		out = append(out, ip.String())
+	}
+	return out
+}
+
+func daysLeft(d time.Duration) int {
+	return int(d.Hours() / 24)
+}
@@ -0,0 +1,81 @@
+package probe
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTLSServer(t *testing.T) (*httptest.Server, *x509.CertPool) {
+	t.Helper()
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+	return s, pool
+}
+
+func TestTLSChecker_ValidCert(t *testing.T) {
+	s, pool := newTLSServer(t)
+	defer s.Close()
+
+	chk := NewTLSChecker(2*time.Second, 14*24*time.Hour).(*tlsChecker)
+	chk.roots = pool
+	out := chk.Check(context.Background(), s.URL)
+	if !out.Success || out.Degraded {
+		t.Fatalf("want healthy success, got %+v", out)
+	}
+	if out.TLS == nil || !out.TLS.ChainValid || out.TLS.NotAfter.IsZero() || out.TLS.Issuer == "" {
+		t.Fatalf("want cert info, got %+v", out.TLS)
+	}
+	if len(out.TLS.SANs) == 0 {
+		t.Fatalf("want SANs recorded")
+	}
+}
+
+func TestTLSChecker_ExpiringWithinWindow(t *testing.T) {
+	s, pool := newTLSServer(t)
+	defer s.Close()
+
+	// test cert is valid for decades; a huge window makes it "expiring"
+	chk := NewTLSChecker(2*time.Second, 200*365*24*time.Hour).(*tlsChecker)
+	chk.roots = pool
+	out := chk.Check(context.Background(), s.URL)
+	if !out.Success || !out.Degraded || !out.TLS.Expiring {
+		t.Fatalf("want degraded success, got %+v", out)
+	}
+	if !strings.Contains(out.Message, "expires in") {
+		t.Fatalf("unexpected message %q", out.Message)
+	}
+}
+
+func TestTLSChecker_UntrustedChain(t *testing.T) {
+	s, _ := newTLSServer(t)
+	defer s.Close()
+
+	out := NewTLSChecker(2*time.Second, 14*24*time.Hour).Check(context.Background(), s.URL)
+	if out.Success {
+		t.Fatalf("want failure for untrusted chain, got %+v", out)
+	}
+	if out.TLS == nil || out.TLS.ChainValid || out.TLS.ChainError == "" {
+		t.Fatalf("want chain error recorded, got %+v", out.TLS)
+	}
+}
+
+func TestWithTLS_MergesDegraded(t *testing.T) {
+	inner := &fakeChecker{results: []CheckResult{{Success: true, StatusCode: 200, Message: "200 OK"}}}
+	tlsChk := &fakeChecker{results: []CheckResult{{Success: true, Degraded: true, Message: "certificate expires in 3 days"}}}
+
+	out := WithTLS(inner, tlsChk).Check(context.Background(), "https://example.com")
+	if !out.Success || !out.Degraded || out.StatusCode != 200 {
+		t.Fatalf("want degraded 200, got %+v", out)
+	}
+	if !strings.Contains(out.Message, "expires in 3 days") {
+		t.Fatalf("unexpected message %q", out.Message)
+	}
+}
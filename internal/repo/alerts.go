@@ -6,12 +6,21 @@ import (
 )
 
 // AlertRecord holds last-known state and the last time we sent a notification
-// for a target. last_state is the last UP/DOWN we saw, last_sent_at is the
-// last time we sent a notification (used for cooldown).
+// for a target. last_state is the last UP/DOWN we announced (or accepted
+// without an alert, e.g. within the cooldown); it is not updated while the
+// target is flapping, when only its results track the observed state.
+// last_sent_at is the last time we sent a notification (used for cooldown).
+// cert_notified_for is the certificate NotAfter we last sent an "expiring"
+// notice for, so each certificate is announced once. flapping is set while
+// DOWN/RECOVERED notifications are suppressed because the target changes
+// state too often; degraded while its latency is over the target's threshold.
 type AlertRecord struct {
-	TargetID   string
-	LastState  bool
-	LastSentAt *time.Time
+	TargetID        string
+	LastState       bool
+	LastSentAt      *time.Time
+	CertNotifiedFor *time.Time
+	Flapping        bool
+	Degraded        bool
 }
 
 // AlertStore is implemented by a persistence layer to store alert state.
@@ -20,4 +29,14 @@ type AlertStore interface {
 	Get(ctx context.Context, targetID string) (*AlertRecord, error)
 	// Set upserts the record. If sentAt.IsZero() we store NULL for last_sent_at.
 	Set(ctx context.Context, targetID string, lastState bool, sentAt time.Time) error
+	// SetCertNotified records that an expiry notice for the certificate with
+	// the given NotAfter was sent. It leaves the up/down state untouched, or
+	// creates the record as up (the certificate was just served) if none exists.
+	SetCertNotified(ctx context.Context, targetID string, notAfter time.Time) error
+	// SetFlapping marks an existing record as flapping or settled. It leaves
+	// the up/down state untouched.
+	SetFlapping(ctx context.Context, targetID string, flapping bool) error
+	// SetDegraded marks an existing record as latency-degraded or not. It
+	// leaves the up/down state untouched.
+	SetDegraded(ctx context.Context, targetID string, degraded bool) error
 }
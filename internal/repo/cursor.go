@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor marks a position in a newest-first listing: the next page starts
+// strictly after (CheckedAt, ID).
+type Cursor struct {
+	CheckedAt time.Time
+	ID        int64
+}
+
+// Encode returns an opaque, URL-safe form of c.
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CheckedAt.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a value produced by Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errors.New("invalid cursor")
+	}
+	ns, err1 := strconv.ParseInt(ts, 10, 64)
+	n, err2 := strconv.ParseInt(id, 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &Cursor{CheckedAt: time.Unix(0, ns).UTC(), ID: n}, nil
+}
+
+// Precedes reports whether (t, id) comes after c in a newest-first listing.
+func (c Cursor) Precedes(t time.Time, id int64) bool {
+	return t.Before(c.CheckedAt) || (t.Equal(c.CheckedAt) && id < c.ID)
+}
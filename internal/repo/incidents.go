@@ -0,0 +1,31 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+// IncidentStore keeps outage records derived from check results.
+type IncidentStore interface {
+	// TrackIncident folds one result into the target's incidents: a failure
+	// opens an incident (or counts against the open one), a success closes it.
+	TrackIncident(ctx context.Context, r *domain.CheckResult) error
+	// Incidents lists incidents newest first.
+	Incidents(ctx context.Context, q IncidentQuery) ([]domain.Incident, error)
+	// Incident returns nil, nil if there's no incident with that ID.
+	Incident(ctx context.Context, id int64) (*domain.Incident, error)
+	// AckIncident records who acknowledged the incident and when, keeping the
+	// first acknowledgement. It returns ErrNotFound for an unknown ID.
+	AckIncident(ctx context.Context, id int64, by string, at time.Time) (*domain.Incident, error)
+	// SetEscalation records how many escalation tiers have been notified.
+	SetEscalation(ctx context.Context, id int64, level int) error
+}
+
+// IncidentQuery filters Incidents. Empty TargetID means all targets.
+type IncidentQuery struct {
+	TargetID string
+	OpenOnly bool
+	Limit    int
+}
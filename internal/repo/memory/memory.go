@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,13 +13,28 @@ import (
 type Store struct {
 	mu      sync.RWMutex
 	targets map[domain.TargetID]*domain.Target
-	results []*domain.CheckResult
+	results []storedResult
+	lastID  int64
+	alerts  map[string]repo.AlertRecord
+
+	incidents      []*domain.Incident
+	lastIncidentID int64
+
+	notifications      []*repo.Notification
+	lastNotificationID int64
+}
+
+// storedResult pairs a result with a sequence ID, like the results.id column.
+type storedResult struct {
+	id int64
+	*domain.CheckResult
 }
 
 func New() *Store {
 	return &Store{
 		targets: make(map[domain.TargetID]*domain.Target),
-		results: make([]*domain.CheckResult, 0, 128),
+		results: make([]storedResult, 0, 128),
+		alerts:  make(map[string]repo.AlertRecord),
 	}
 }
 
@@ -45,10 +61,76 @@ func (m *Store) List(ctx context.Context) ([]*domain.Target, error) {
 	return out, nil
 }
 
+func (m *Store) Find(ctx context.Context, id domain.TargetID) (*domain.Target, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.targets[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (m *Store) Update(ctx context.Context, t *domain.Target) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur, ok := m.targets[t.ID]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	cp := *t
+	cp.CreatedAt = cur.CreatedAt
+	cp.Paused = cur.Paused
+	m.targets[t.ID] = &cp
+	return nil
+}
+
+func (m *Store) Delete(ctx context.Context, id domain.TargetID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.targets[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(m.targets, id)
+	delete(m.alerts, string(id))
+
+	kept := m.results[:0]
+	for _, r := range m.results {
+		if r.TargetID != id {
+			kept = append(kept, r)
+		}
+	}
+	m.results = kept
+
+	keptInc := m.incidents[:0]
+	for _, inc := range m.incidents {
+		if inc.TargetID != id {
+			keptInc = append(keptInc, inc)
+		}
+	}
+	m.incidents = keptInc
+	return nil
+}
+
+func (m *Store) SetPaused(ctx context.Context, id domain.TargetID, paused bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur, ok := m.targets[id]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	cp := *cur
+	cp.Paused = paused
+	m.targets[id] = &cp
+	return nil
+}
+
 func (m *Store) Append(ctx context.Context, r *domain.CheckResult) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.results = append(m.results, r)
+	m.lastID++
+	m.results = append(m.results, storedResult{id: m.lastID, CheckResult: r})
 	return nil
 }
 
@@ -60,7 +142,7 @@ func (m *Store) Latest(ctx context.Context) ([]repo.LatestRow, error) {
 	for _, r := range m.results {
 		cur := latest[r.TargetID]
 		if cur == nil || r.CheckedAt.After(cur.CheckedAt) {
-			latest[r.TargetID] = r
+			latest[r.TargetID] = r.CheckResult
 		}
 	}
 
@@ -88,7 +170,357 @@ func (m *Store) Latest(ctx context.Context) ([]repo.LatestRow, error) {
 			LatencyMS:  lat,
 			Reason:     r.Reason,
 			CheckedAt:  r.CheckedAt,
+			Degraded:   r.Degraded,
+			Cert:       r.Cert,
 		})
 	}
 	return out, nil
 }
+
+func (m *Store) History(ctx context.Context, q repo.HistoryQuery) (repo.HistoryPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rows []storedResult
+	for _, r := range m.results {
+		if string(r.TargetID) != q.TargetID {
+			continue
+		}
+		if !q.From.IsZero() && r.CheckedAt.Before(q.From) {
+			continue
+		}
+		if !q.To.IsZero() && !r.CheckedAt.Before(q.To) {
+			continue
+		}
+		if q.After != nil && !q.After.Precedes(r.CheckedAt, r.id) {
+			continue
+		}
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].CheckedAt.Equal(rows[j].CheckedAt) {
+			return rows[i].CheckedAt.After(rows[j].CheckedAt)
+		}
+		return rows[i].id > rows[j].id
+	})
+
+	var page repo.HistoryPage
+	if q.Limit > 0 && len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		last := rows[len(rows)-1]
+		page.Next = &repo.Cursor{CheckedAt: last.CheckedAt, ID: last.id}
+	}
+	page.Results = make([]domain.Result, 0, len(rows))
+	for _, r := range rows {
+		page.Results = append(page.Results, toResult(r))
+	}
+	return page, nil
+}
+
+func (m *Store) Uptime(ctx context.Context, targetID string, from, to time.Time) (domain.UptimeReport, error) {
+	seed, samples := m.samples(targetID, from, to)
+	return repo.ComputeUptime(targetID, seed, samples, from, to), nil
+}
+
+func (m *Store) DailyUptime(ctx context.Context, targetID string, from time.Time, days int) ([]domain.UptimeReport, error) {
+	seed, samples := m.samples(targetID, from, from.Add(time.Duration(days)*24*time.Hour))
+	return repo.ComputeDaily(targetID, seed, samples, from, days), nil
+}
+
+// samples returns the target's results in [from, to), oldest first, and the
+// last one before from.
+func (m *Store) samples(targetID string, from, to time.Time) (*repo.Sample, []repo.Sample) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var seed *repo.Sample
+	var samples []repo.Sample
+	for _, r := range m.results {
+		if string(r.TargetID) != targetID || !r.CheckedAt.Before(to) {
+			continue
+		}
+		s := repo.Sample{Up: r.Up, LatencyMS: r.LatencyMS, CheckedAt: r.CheckedAt}
+		if r.CheckedAt.Before(from) {
+			if seed == nil || s.CheckedAt.After(seed.CheckedAt) {
+				seed = &s
+			}
+			continue
+		}
+		samples = append(samples, s)
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].CheckedAt.Before(samples[j].CheckedAt) })
+	return seed, samples
+}
+
+func toResult(r storedResult) domain.Result {
+	out := domain.Result{
+		ID:        r.id,
+		TargetID:  r.TargetID,
+		Up:        r.Up,
+		Reason:    r.Reason,
+		CheckedAt: r.CheckedAt,
+		Degraded:  r.Degraded,
+	}
+	if r.HTTPStatus != 0 {
+		v := r.HTTPStatus
+		out.HTTPStatus = &v
+	}
+	lat := r.LatencyMS
+	out.LatencyMS = &lat
+	return out
+}
+
+// ---- AlertStore ----
+
+func (m *Store) Get(ctx context.Context, targetID string) (*repo.AlertRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.alerts[targetID]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func (m *Store) Set(ctx context.Context, targetID string, lastState bool, sentAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ts *time.Time
+	if !sentAt.IsZero() {
+		ts = &sentAt
+	}
+	r := m.alerts[targetID]
+	r.TargetID = targetID
+	r.LastState = lastState
+	r.LastSentAt = ts
+	m.alerts[targetID] = r
+	return nil
+}
+
+func (m *Store) SetCertNotified(ctx context.Context, targetID string, notAfter time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.alerts[targetID]
+	if !ok {
+		r = repo.AlertRecord{TargetID: targetID, LastState: true}
+	}
+	r.CertNotifiedFor = &notAfter
+	m.alerts[targetID] = r
+	return nil
+}
+
+func (m *Store) SetFlapping(ctx context.Context, targetID string, flapping bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.alerts[targetID]
+	if !ok {
+		return nil
+	}
+	r.Flapping = flapping
+	m.alerts[targetID] = r
+	return nil
+}
+
+func (m *Store) SetDegraded(ctx context.Context, targetID string, degraded bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.alerts[targetID]
+	if !ok {
+		return nil
+	}
+	r.Degraded = degraded
+	m.alerts[targetID] = r
+	return nil
+}
+
+// ---- IncidentStore ----
+
+func (m *Store) TrackIncident(ctx context.Context, r *domain.CheckResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var open *domain.Incident
+	for _, inc := range m.incidents {
+		if inc.TargetID == r.TargetID && inc.Open() {
+			open = inc
+			break
+		}
+	}
+	switch {
+	case !r.Up && open == nil:
+		m.lastIncidentID++
+		m.incidents = append(m.incidents, &domain.Incident{
+			ID:           m.lastIncidentID,
+			TargetID:     r.TargetID,
+			StartedAt:    r.CheckedAt,
+			FirstReason:  r.Reason,
+			FailedChecks: 1,
+		})
+	case !r.Up:
+		open.FailedChecks++
+	case open != nil:
+		end := r.CheckedAt
+		open.EndedAt = &end
+	}
+	return nil
+}
+
+func (m *Store) Incidents(ctx context.Context, q repo.IncidentQuery) ([]domain.Incident, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]domain.Incident, 0)
+	for i := len(m.incidents) - 1; i >= 0; i-- {
+		inc := m.incidents[i]
+		if q.TargetID != "" && string(inc.TargetID) != q.TargetID {
+			continue
+		}
+		if q.OpenOnly && !inc.Open() {
+			continue
+		}
+		out = append(out, *inc)
+		if q.Limit > 0 && len(out) == q.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (m *Store) Incident(ctx context.Context, id int64) (*domain.Incident, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, inc := range m.incidents {
+		if inc.ID == id {
+			cp := *inc
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *Store) AckIncident(ctx context.Context, id int64, by string, at time.Time) (*domain.Incident, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, inc := range m.incidents {
+		if inc.ID == id {
+			if inc.AckedAt == nil {
+				inc.AckedAt = &at
+				inc.AckedBy = by
+			}
+			cp := *inc
+			return &cp, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+func (m *Store) SetEscalation(ctx context.Context, id int64, level int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, inc := range m.incidents {
+		if inc.ID == id {
+			inc.Escalation = level
+			return nil
+		}
+	}
+	return repo.ErrNotFound
+}
+
+// ---- OutboxStore ----
+
+func (m *Store) Enqueue(ctx context.Context, ns []*repo.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, n := range ns {
+		if n.CreatedAt.IsZero() {
+			n.CreatedAt = time.Now().UTC()
+		}
+		if n.NextAttemptAt.IsZero() {
+			n.NextAttemptAt = n.CreatedAt
+		}
+		m.lastNotificationID++
+		n.ID = m.lastNotificationID
+		n.Status = repo.NotificationPending
+		cp := *n
+		m.notifications = append(m.notifications, &cp)
+	}
+	return nil
+}
+
+func (m *Store) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]repo.Notification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// m.notifications is in ID order: the first pending row per target and
+	// notifier blocks the rest.
+	type pair struct{ target, notifier string }
+	blocked := map[pair]bool{}
+	var due []*repo.Notification
+	for _, n := range m.notifications {
+		if n.Status != repo.NotificationPending {
+			continue
+		}
+		k := pair{n.TargetID, n.Notifier}
+		if !blocked[k] && !n.NextAttemptAt.After(now) {
+			due = append(due, n)
+		}
+		blocked[k] = true
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	out := make([]repo.Notification, 0, len(due))
+	for _, n := range due {
+		n.NextAttemptAt = now.Add(lease)
+		out = append(out, copyNotification(n))
+	}
+	return out, nil
+}
+
+func (m *Store) RecordAttempt(ctx context.Context, id int64, a repo.DeliveryAttempt, status string, next time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, n := range m.notifications {
+		if n.ID == id {
+			n.Attempts++
+			n.LastError = a.Error
+			n.Log = append(n.Log, a)
+			n.Status = status
+			n.NextAttemptAt = next
+			if status == repo.NotificationSent {
+				at := a.At
+				n.SentAt = &at
+			}
+			return nil
+		}
+	}
+	return repo.ErrNotFound
+}
+
+func (m *Store) Notifications(ctx context.Context, q repo.NotificationQuery) ([]repo.Notification, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]repo.Notification, 0)
+	for i := len(m.notifications) - 1; i >= 0; i-- {
+		n := m.notifications[i]
+		if q.Status != "" && n.Status != q.Status {
+			continue
+		}
+		if q.TargetID != "" && n.TargetID != q.TargetID {
+			continue
+		}
+		out = append(out, copyNotification(n))
+		if q.Limit > 0 && len(out) == q.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// copyNotification detaches the log so callers can't alias the stored one.
+func copyNotification(n *repo.Notification) repo.Notification {
+	cp := *n
+	cp.Log = append([]repo.DeliveryAttempt(nil), n.Log...)
+	return cp
+}
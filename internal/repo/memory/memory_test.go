@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -80,3 +81,208 @@ func TestMemoryStore_Add_List_Append_Latest(t *testing.T) {
 		t.Fatalf("want Reason set")
 	}
 }
+
+func TestMemoryStore_AlertGetSet(t *testing.T) {
+	ctx := context.Background()
+	st := New()
+
+	rec, err := st.Get(ctx, "T1")
+	if err != nil || rec != nil {
+		t.Fatalf("expected nil, got %+v err=%v", rec, err)
+	}
+
+	if err := st.Set(ctx, "T1", false, time.Time{}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	rec, err = st.Get(ctx, "T1")
+	if err != nil || rec == nil || rec.LastState || rec.LastSentAt != nil {
+		t.Fatalf("unexpected: %+v err=%v", rec, err)
+	}
+
+	now := time.Now()
+	if err := st.Set(ctx, "T1", true, now); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	rec, err = st.Get(ctx, "T1")
+	if err != nil || rec == nil || !rec.LastState || rec.LastSentAt == nil || !rec.LastSentAt.Equal(now) {
+		t.Fatalf("unexpected: %+v err=%v", rec, err)
+	}
+
+	// flapping survives state updates
+	if err := st.SetFlapping(ctx, "T1", true); err != nil {
+		t.Fatalf("SetFlapping: %v", err)
+	}
+	_ = st.Set(ctx, "T1", false, time.Time{})
+	if rec, _ = st.Get(ctx, "T1"); !rec.Flapping || rec.LastState {
+		t.Fatalf("unexpected: %+v", rec)
+	}
+}
+
+func TestMemoryStore_SetCertNotifiedWithoutRecord(t *testing.T) {
+	ctx := context.Background()
+	st := New()
+
+	notAfter := time.Now().Add(7 * 24 * time.Hour)
+	if err := st.SetCertNotified(ctx, "T1", notAfter); err != nil {
+		t.Fatalf("SetCertNotified: %v", err)
+	}
+	rec, err := st.Get(ctx, "T1")
+	if err != nil || rec == nil || rec.CertNotifiedFor == nil || !rec.CertNotifiedFor.Equal(notAfter) || !rec.LastState {
+		t.Fatalf("want the notice recorded on a new up record, got %+v err=%v", rec, err)
+	}
+
+	// the up/down state of an existing record is kept
+	_ = st.Set(ctx, "T2", false, time.Time{})
+	_ = st.SetCertNotified(ctx, "T2", notAfter)
+	if rec, _ = st.Get(ctx, "T2"); rec.LastState || rec.CertNotifiedFor == nil {
+		t.Fatalf("unexpected: %+v", rec)
+	}
+}
+
+func TestMemoryStore_History_RangeAndPages(t *testing.T) {
+	ctx := context.Background()
+	st := New()
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		_ = st.Append(ctx, &domain.CheckResult{TargetID: "A", Up: i%2 == 0, LatencyMS: float64(i), CheckedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+	_ = st.Append(ctx, &domain.CheckResult{TargetID: "B", Up: true, CheckedAt: base})
+
+	// range [base+1m, base+4m) -> minutes 3,2,1 newest first, two per page
+	q := repo.HistoryQuery{TargetID: "A", From: base.Add(time.Minute), To: base.Add(4 * time.Minute), Limit: 2}
+	p1, err := st.History(ctx, q)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(p1.Results) != 2 || p1.Next == nil {
+		t.Fatalf("want 2 rows and a cursor, got %d next=%v", len(p1.Results), p1.Next)
+	}
+	if !p1.Results[0].CheckedAt.Equal(base.Add(3*time.Minute)) || !p1.Results[1].CheckedAt.Equal(base.Add(2*time.Minute)) {
+		t.Fatalf("unexpected order: %+v", p1.Results)
+	}
+
+	// round-trip the cursor like the API does
+	cur, err := repo.DecodeCursor(p1.Next.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	q.After = cur
+	p2, err := st.History(ctx, q)
+	if err != nil {
+		t.Fatalf("History page 2: %v", err)
+	}
+	if len(p2.Results) != 1 || p2.Next != nil || !p2.Results[0].CheckedAt.Equal(base.Add(time.Minute)) {
+		t.Fatalf("unexpected page 2: %+v next=%v", p2.Results, p2.Next)
+	}
+}
+
+func TestMemoryStore_TrackIncident(t *testing.T) {
+	ctx := context.Background()
+	st := New()
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	track := func(up bool, min int, reason string) {
+		if err := st.TrackIncident(ctx, &domain.CheckResult{TargetID: "A", Up: up, Reason: reason, CheckedAt: base.Add(time.Duration(min) * time.Minute)}); err != nil {
+			t.Fatalf("TrackIncident: %v", err)
+		}
+	}
+
+	track(true, 0, "200 OK")   // up, nothing open -> no incident
+	track(false, 1, "503")     // opens #1
+	track(false, 2, "timeout") // counts against #1
+	track(true, 3, "200 OK")   // closes #1
+	track(false, 4, "refused") // opens #2
+
+	all, err := st.Incidents(ctx, repo.IncidentQuery{TargetID: "A"})
+	if err != nil {
+		t.Fatalf("Incidents: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("want 2 incidents, got %+v", all)
+	}
+	first := all[1] // newest first
+	if first.FailedChecks != 2 || first.FirstReason != "503" || first.EndedAt == nil || !first.EndedAt.Equal(base.Add(3*time.Minute)) {
+		t.Fatalf("unexpected closed incident: %+v", first)
+	}
+
+	open, _ := st.Incidents(ctx, repo.IncidentQuery{OpenOnly: true})
+	if len(open) != 1 || open[0].FirstReason != "refused" || !open[0].Open() {
+		t.Fatalf("unexpected open incidents: %+v", open)
+	}
+
+	got, _ := st.Incident(ctx, first.ID)
+	if got == nil || got.ID != first.ID {
+		t.Fatalf("Incident by id: %+v", got)
+	}
+	if got, _ := st.Incident(ctx, 999); got != nil {
+		t.Fatalf("want nil for unknown id, got %+v", got)
+	}
+}
+
+func TestMemoryStore_AckIncidentKeepsFirstAck(t *testing.T) {
+	ctx := context.Background()
+	st := New()
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	_ = st.TrackIncident(ctx, &domain.CheckResult{TargetID: "A", Up: false, CheckedAt: at})
+	open, _ := st.Incidents(ctx, repo.IncidentQuery{OpenOnly: true})
+	id := open[0].ID
+
+	inc, err := st.AckIncident(ctx, id, "alice", at.Add(time.Minute))
+	if err != nil || inc.AckedBy != "alice" || !inc.AckedAt.Equal(at.Add(time.Minute)) {
+		t.Fatalf("first ack: %+v %v", inc, err)
+	}
+	inc, _ = st.AckIncident(ctx, id, "bob", at.Add(2*time.Minute))
+	if inc.AckedBy != "alice" || !inc.AckedAt.Equal(at.Add(time.Minute)) {
+		t.Fatalf("second ack must not overwrite: %+v", inc)
+	}
+	if _, err := st.AckIncident(ctx, 999, "x", at); !errors.Is(err, repo.ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	if err := st.SetEscalation(ctx, id, 2); err != nil {
+		t.Fatalf("SetEscalation: %v", err)
+	}
+	if got, _ := st.Incident(ctx, id); got.Escalation != 2 {
+		t.Fatalf("escalation level = %d", got.Escalation)
+	}
+}
+
+func TestMemoryStore_Outbox(t *testing.T) {
+	ctx := context.Background()
+	st := New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	a := &repo.Notification{Notifier: "slack", Kind: "down", TargetID: "A", CreatedAt: now}
+	b := &repo.Notification{Notifier: "email", Kind: "down", TargetID: "B", CreatedAt: now, NextAttemptAt: now.Add(time.Hour)}
+	if err := st.Enqueue(ctx, []*repo.Notification{a, b}); err != nil || a.ID == 0 || b.ID == 0 {
+		t.Fatalf("Enqueue: ids %d %d, %v", a.ID, b.ID, err)
+	}
+
+	due, _ := st.ClaimDue(ctx, now, time.Minute, 10)
+	if len(due) != 1 || due[0].ID != a.ID {
+		t.Fatalf("want only A due, got %+v", due)
+	}
+	if again, _ := st.ClaimDue(ctx, now, time.Minute, 10); len(again) != 0 {
+		t.Fatalf("claimed row must be leased, got %+v", again)
+	}
+
+	if err := st.RecordAttempt(ctx, a.ID, repo.DeliveryAttempt{At: now, Error: "boom"}, repo.NotificationPending, now.Add(time.Second)); err != nil {
+		t.Fatalf("RecordAttempt: %v", err)
+	}
+	if err := st.RecordAttempt(ctx, a.ID, repo.DeliveryAttempt{At: now.Add(time.Second)}, repo.NotificationSent, now.Add(time.Second)); err != nil {
+		t.Fatalf("RecordAttempt: %v", err)
+	}
+	if err := st.RecordAttempt(ctx, 999, repo.DeliveryAttempt{At: now}, repo.NotificationSent, now); !errors.Is(err, repo.ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	sent, _ := st.Notifications(ctx, repo.NotificationQuery{Status: repo.NotificationSent})
+	if len(sent) != 1 || sent[0].Attempts != 2 || len(sent[0].Log) != 2 || sent[0].SentAt == nil || sent[0].Log[0].Error != "boom" {
+		t.Fatalf("unexpected sent notifications: %+v", sent)
+	}
+	if all, _ := st.Notifications(ctx, repo.NotificationQuery{}); len(all) != 2 || all[0].ID != b.ID {
+		t.Fatalf("want newest first, got %+v", all)
+	}
+	if byTarget, _ := st.Notifications(ctx, repo.NotificationQuery{TargetID: "B"}); len(byTarget) != 1 {
+		t.Fatalf("target filter: %+v", byTarget)
+	}
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// Notification delivery states.
+const (
+	NotificationPending = "pending" // waiting for its (next) attempt
+	NotificationSent    = "sent"
+	NotificationDead    = "dead" // gave up; kept for inspection
+)
+
+// Notification is one alert queued for one notifier in the outbox.
+type Notification struct {
+	ID       int64  `json:"id"`
+	Notifier string `json:"notifier"`
+	Kind     string `json:"kind"`
+	TargetID string `json:"target_id,omitempty"`
+	Title    string `json:"title"`
+	// Payload is the JSON-encoded notify.Event to deliver.
+	Payload       []byte            `json:"-"`
+	Status        string            `json:"status"`
+	Attempts      int               `json:"attempts"`
+	LastError     string            `json:"last_error,omitempty"`
+	Log           []DeliveryAttempt `json:"log"`
+	CreatedAt     time.Time         `json:"created_at"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	SentAt        *time.Time        `json:"sent_at,omitempty"`
+}
+
+// DeliveryAttempt is one try at delivering a notification.
+type DeliveryAttempt struct {
+	At    time.Time `json:"at"`
+	Error string    `json:"error,omitempty"`
+}
+
+// OutboxStore persists notifications until they are delivered.
+type OutboxStore interface {
+	// Enqueue stores pending notifications due at their NextAttemptAt
+	// (CreatedAt if zero), setting their IDs.
+	Enqueue(ctx context.Context, ns []*Notification) error
+	// ClaimDue returns up to limit pending notifications due at now, oldest
+	// first, and moves their NextAttemptAt to now+lease so that a crashed
+	// worker's claims are retried but concurrent workers skip them. A
+	// notification waits while an older one for the same target and notifier
+	// is pending, so e.g. a RECOVERED never overtakes a DOWN being retried.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Notification, error)
+	// RecordAttempt logs an attempt and sets the resulting status; next is
+	// when a pending notification is tried again.
+	RecordAttempt(ctx context.Context, id int64, a DeliveryAttempt, status string, next time.Time) error
+	// Notifications lists notifications newest first.
+	Notifications(ctx context.Context, q NotificationQuery) ([]Notification, error)
+}
+
+// NotificationQuery filters Notifications; empty fields match everything.
+type NotificationQuery struct {
+	Status   string
+	TargetID string
+	Limit    int
+}
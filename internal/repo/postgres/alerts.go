@@ -9,11 +9,11 @@ import (
 )
 
 func (s *Store) Get(ctx context.Context, targetID string) (*repo.AlertRecord, error) {
-	const q = `SELECT last_state, last_sent_at FROM alerts WHERE target_id=$1`
+	const q = `SELECT last_state, last_sent_at, cert_notified_for, flapping, degraded FROM alerts WHERE target_id=$1`
 	var r repo.AlertRecord
 	r.TargetID = targetID
-	var lastSent *time.Time
-	err := s.pool.QueryRow(ctx, q, targetID).Scan(&r.LastState, &lastSent)
+	var lastSent, certFor *time.Time
+	err := s.pool.QueryRow(ctx, q, targetID).Scan(&r.LastState, &lastSent, &certFor, &r.Flapping, &r.Degraded)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -21,6 +21,7 @@ func (s *Store) Get(ctx context.Context, targetID string) (*repo.AlertRecord, er
 		return nil, err
 	}
 	r.LastSentAt = lastSent
+	r.CertNotifiedFor = certFor
 	return &r, nil
 }
 
@@ -38,3 +39,26 @@ func (s *Store) Set(ctx context.Context, targetID string, lastState bool, sentAt
 	_, err := s.pool.Exec(ctx, q, targetID, lastState, ts)
 	return err
 }
+
+func (s *Store) SetCertNotified(ctx context.Context, targetID string, notAfter time.Time) error {
+	const q = `
+		INSERT INTO alerts (target_id, last_state, cert_notified_for)
+		VALUES ($1,true,$2)
+		ON CONFLICT (target_id)
+		DO UPDATE SET cert_notified_for=EXCLUDED.cert_notified_for
+	`
+	_, err := s.pool.Exec(ctx, q, targetID, notAfter)
+	return err
+}
+
+func (s *Store) SetFlapping(ctx context.Context, targetID string, flapping bool) error {
+	const q = `UPDATE alerts SET flapping=$2 WHERE target_id=$1`
+	_, err := s.pool.Exec(ctx, q, targetID, flapping)
+	return err
+}
+
+func (s *Store) SetDegraded(ctx context.Context, targetID string, degraded bool) error {
+	const q = `UPDATE alerts SET degraded=$2 WHERE target_id=$1`
+	_, err := s.pool.Exec(ctx, q, targetID, degraded)
+	return err
+}
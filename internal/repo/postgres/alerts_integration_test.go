@@ -52,4 +52,14 @@ func TestAlertsCRUD(t *testing.T) {
 	if err != nil || rec == nil || rec.LastSentAt == nil || rec.LastState != true {
 		t.Fatalf("unexpected2: %+v err=%v", rec, err)
 	}
+
+	// cert notice without a prior record
+	notAfter := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Microsecond)
+	if err := store.SetCertNotified(ctx, "T2", notAfter); err != nil {
+		t.Fatalf("set cert: %v", err)
+	}
+	rec, err = store.Get(ctx, "T2")
+	if err != nil || rec == nil || rec.CertNotifiedFor == nil || !rec.CertNotifiedFor.Equal(notAfter) {
+		t.Fatalf("unexpected3: %+v err=%v", rec, err)
+	}
 }
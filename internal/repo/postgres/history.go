@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+func (s *Store) History(ctx context.Context, q repo.HistoryQuery) (repo.HistoryPage, error) {
+	var from, to, afterAt *time.Time
+	var afterID int64
+	if !q.From.IsZero() {
+		from = &q.From
+	}
+	if !q.To.IsZero() {
+		to = &q.To
+	}
+	if q.After != nil {
+		afterAt, afterID = &q.After.CheckedAt, q.After.ID
+	}
+	// fetch one extra row to learn whether another page exists
+	var limit *int
+	if q.Limit > 0 {
+		n := q.Limit + 1
+		limit = &n
+	}
+
+	rows, err := s.pool.Query(ctx, `
+SELECT id, target_id, up, http_status, latency_ms, reason, checked_at, degraded
+  FROM results
+ WHERE target_id = $1
+   AND ($2::timestamptz IS NULL OR checked_at >= $2)
+   AND ($3::timestamptz IS NULL OR checked_at <  $3)
+   AND ($4::timestamptz IS NULL OR (checked_at, id) < ($4, $5))
+ ORDER BY checked_at DESC, id DESC
+ LIMIT $6`,
+		q.TargetID, from, to, afterAt, afterID, limit,
+	)
+	if err != nil {
+		return repo.HistoryPage{}, fmt.Errorf("history: %w", err)
+	}
+	defer rows.Close()
+
+	page := repo.HistoryPage{Results: []domain.Result{}} // [] rather than null in JSON
+	for rows.Next() {
+		var (
+			r        domain.Result
+			tid      string
+			httpNull sql.NullInt32
+		)
+		if err := rows.Scan(&r.ID, &tid, &r.Up, &httpNull, &r.LatencyMS, &r.Reason, &r.CheckedAt, &r.Degraded); err != nil {
+			return repo.HistoryPage{}, fmt.Errorf("scan history: %w", err)
+		}
+		r.TargetID = domain.TargetID(tid)
+		if httpNull.Valid {
+			v := int(httpNull.Int32)
+			r.HTTPStatus = &v
+		}
+		page.Results = append(page.Results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return repo.HistoryPage{}, err
+	}
+
+	if q.Limit > 0 && len(page.Results) > q.Limit {
+		page.Results = page.Results[:q.Limit]
+		last := page.Results[len(page.Results)-1]
+		page.Next = &repo.Cursor{CheckedAt: last.CheckedAt, ID: last.ID}
+	}
+	return page, nil
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+var _ repo.IncidentStore = (*Store)(nil)
+
+const incidentCols = `id, target_id, started_at, ended_at, first_reason, failed_checks, acked_at, acked_by, escalation_level`
+
+func (s *Store) TrackIncident(ctx context.Context, r *domain.CheckResult) error {
+	if r.Up {
+		_, err := s.pool.Exec(ctx,
+			`UPDATE incidents SET ended_at=$2 WHERE target_id=$1 AND ended_at IS NULL`,
+			string(r.TargetID), r.CheckedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("close incident: %w", err)
+		}
+		return nil
+	}
+	// At most one open incident per target (partial unique index).
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO incidents (target_id, started_at, first_reason, failed_checks)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (target_id) WHERE ended_at IS NULL
+		DO UPDATE SET failed_checks = incidents.failed_checks + 1`,
+		string(r.TargetID), r.CheckedAt, r.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("open incident: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Incidents(ctx context.Context, q repo.IncidentQuery) ([]domain.Incident, error) {
+	var tid *string
+	if q.TargetID != "" {
+		tid = &q.TargetID
+	}
+	var limit *int
+	if q.Limit > 0 {
+		limit = &q.Limit
+	}
+	rows, err := s.pool.Query(ctx, `
+SELECT `+incidentCols+`
+  FROM incidents
+ WHERE ($1::text IS NULL OR target_id = $1)
+   AND (NOT $2 OR ended_at IS NULL)
+ ORDER BY started_at DESC, id DESC
+ LIMIT $3`, tid, q.OpenOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list incidents: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Incident, 0)
+	for rows.Next() {
+		inc, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *inc)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) Incident(ctx context.Context, id int64) (*domain.Incident, error) {
+	inc, err := scanIncident(s.pool.QueryRow(ctx, `SELECT `+incidentCols+` FROM incidents WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return inc, err
+}
+
+func (s *Store) AckIncident(ctx context.Context, id int64, by string, at time.Time) (*domain.Incident, error) {
+	inc, err := scanIncident(s.pool.QueryRow(ctx, `
+UPDATE incidents
+   SET acked_at = COALESCE(acked_at, $2),
+       acked_by = CASE WHEN acked_at IS NULL THEN $3 ELSE acked_by END
+ WHERE id = $1
+RETURNING `+incidentCols, id, at, by))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	return inc, err
+}
+
+func (s *Store) SetEscalation(ctx context.Context, id int64, level int) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE incidents SET escalation_level=$2 WHERE id=$1`, id, level)
+	if err != nil {
+		return fmt.Errorf("set escalation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func scanIncident(row pgx.Row) (*domain.Incident, error) {
+	var (
+		inc   domain.Incident
+		tid   string
+		ended *time.Time
+	)
+	if err := row.Scan(&inc.ID, &tid, &inc.StartedAt, &ended, &inc.FirstReason, &inc.FailedChecks,
+		&inc.AckedAt, &inc.AckedBy, &inc.Escalation); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan incident: %w", err)
+	}
+	inc.TargetID = domain.TargetID(tid)
+	inc.EndedAt = ended
+	return &inc, nil
+}
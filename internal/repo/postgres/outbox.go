@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+var _ repo.OutboxStore = (*Store)(nil)
+
+const notificationCols = `id, notifier, kind, target_id, title, payload, status, attempts, last_error, log, created_at, next_attempt_at, sent_at`
+
+func (s *Store) Enqueue(ctx context.Context, ns []*repo.Notification) error {
+	batch := &pgx.Batch{}
+	for _, n := range ns {
+		if n.CreatedAt.IsZero() {
+			n.CreatedAt = time.Now().UTC()
+		}
+		if n.NextAttemptAt.IsZero() {
+			n.NextAttemptAt = n.CreatedAt
+		}
+		n.Status = repo.NotificationPending
+		batch.Queue(`
+INSERT INTO notifications (notifier, kind, target_id, title, payload, created_at, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id`, n.Notifier, n.Kind, n.TargetID, n.Title, n.Payload, n.CreatedAt, n.NextAttemptAt)
+	}
+	// One transaction: an alert is queued for all of its notifiers or none.
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		br := tx.SendBatch(ctx, batch)
+		defer br.Close()
+		for _, n := range ns {
+			if err := br.QueryRow().Scan(&n.ID); err != nil {
+				return fmt.Errorf("enqueue notification: %w", err)
+			}
+		}
+		return br.Close()
+	})
+}
+
+func (s *Store) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]repo.Notification, error) {
+	rows, err := s.pool.Query(ctx, `
+UPDATE notifications
+   SET next_attempt_at = $2
+ WHERE id IN (
+       SELECT id FROM notifications n
+        WHERE status = 'pending' AND next_attempt_at <= $1
+          AND NOT EXISTS (
+              SELECT 1 FROM notifications o
+               WHERE o.status = 'pending' AND o.target_id = n.target_id
+                 AND o.notifier = n.notifier AND o.id < n.id)
+        ORDER BY next_attempt_at, id
+        LIMIT $3
+          FOR UPDATE SKIP LOCKED)
+RETURNING `+notificationCols, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim notifications: %w", err)
+	}
+	out, err := collectNotifications(rows)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (s *Store) RecordAttempt(ctx context.Context, id int64, a repo.DeliveryAttempt, status string, next time.Time) error {
+	entry, err := json.Marshal([]repo.DeliveryAttempt{a})
+	if err != nil {
+		return err
+	}
+	tag, err := s.pool.Exec(ctx, `
+UPDATE notifications
+   SET attempts        = attempts + 1,
+       last_error      = $3,
+       log             = log || $4::jsonb,
+       status          = $5,
+       next_attempt_at = $6,
+       sent_at         = CASE WHEN $5 = 'sent' THEN $2 ELSE sent_at END
+ WHERE id = $1`, id, a.At, a.Error, entry, status, next)
+	if err != nil {
+		return fmt.Errorf("record attempt: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) Notifications(ctx context.Context, q repo.NotificationQuery) ([]repo.Notification, error) {
+	var limit *int
+	if q.Limit > 0 {
+		limit = &q.Limit
+	}
+	rows, err := s.pool.Query(ctx, `
+SELECT `+notificationCols+`
+  FROM notifications
+ WHERE ($1 = '' OR status = $1)
+   AND ($2 = '' OR target_id = $2)
+ ORDER BY id DESC
+ LIMIT $3`, q.Status, q.TargetID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	return collectNotifications(rows)
+}
+
+func collectNotifications(rows pgx.Rows) ([]repo.Notification, error) {
+	defer rows.Close()
+	out := make([]repo.Notification, 0)
+	for rows.Next() {
+		var (
+			n      repo.Notification
+			logRaw []byte
+		)
+		if err := rows.Scan(&n.ID, &n.Notifier, &n.Kind, &n.TargetID, &n.Title, &n.Payload, &n.Status,
+			&n.Attempts, &n.LastError, &logRaw, &n.CreatedAt, &n.NextAttemptAt, &n.SentAt); err != nil {
+			return nil, fmt.Errorf("scan notification: %w", err)
+		}
+		if err := json.Unmarshal(logRaw, &n.Log); err != nil {
+			return nil, fmt.Errorf("decode delivery log: %w", err)
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
@@ -3,9 +3,12 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
@@ -49,10 +52,22 @@ func (s *Store) Add(ctx context.Context, t *domain.Target) error {
 	if t.CreatedAt.IsZero() {
 		t.CreatedAt = time.Now().UTC()
 	}
-	_, err := s.pool.Exec(ctx,
-		`INSERT INTO targets (id, url, created_at)
-		 VALUES ($1, $2, $3)`,
-		string(t.ID), t.URL, t.CreatedAt,
+	httpOpts, err := marshalJSON(t.HTTP)
+	if err != nil {
+		return fmt.Errorf("marshal http options: %w", err)
+	}
+	latency, err := marshalJSON(t.Latency)
+	if err != nil {
+		return fmt.Errorf("marshal latency threshold: %w", err)
+	}
+	labels, err := marshalLabels(t.Labels)
+	if err != nil {
+		return fmt.Errorf("marshal labels: %w", err)
+	}
+	_, err = s.pool.Exec(ctx,
+		`INSERT INTO targets (id, url, created_at, http, interval_ms, timeout_ms, group_name, down_after, up_after, latency, labels, status_page)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		string(t.ID), t.URL, t.CreatedAt, httpOpts, t.IntervalMS, t.TimeoutMS, t.Group, t.DownAfter, t.UpAfter, latency, labels, t.StatusPage,
 	)
 	if err != nil {
 		return fmt.Errorf("insert target: %w", err)
@@ -62,7 +77,7 @@ func (s *Store) Add(ctx context.Context, t *domain.Target) error {
 
 func (s *Store) List(ctx context.Context) ([]*domain.Target, error) {
 	rows, err := s.pool.Query(ctx,
-		`SELECT id, url, created_at
+		`SELECT `+targetCols+`
 		   FROM targets
 		  ORDER BY created_at DESC, id DESC`)
 	if err != nil {
@@ -72,23 +87,115 @@ func (s *Store) List(ctx context.Context) ([]*domain.Target, error) {
 
 	var out []*domain.Target
 	for rows.Next() {
-		var (
-			id        string
-			url       string
-			createdAt time.Time
-		)
-		if err := rows.Scan(&id, &url, &createdAt); err != nil {
-			return nil, fmt.Errorf("scan target: %w", err)
+		t, err := scanTarget(rows)
+		if err != nil {
+			return nil, err
 		}
-		out = append(out, &domain.Target{
-			ID:        domain.TargetID(id),
-			URL:       url,
-			CreatedAt: createdAt,
-		})
+		out = append(out, t)
 	}
 	return out, rows.Err()
 }
 
+func (s *Store) Find(ctx context.Context, id domain.TargetID) (*domain.Target, error) {
+	t, err := scanTarget(s.pool.QueryRow(ctx, `SELECT `+targetCols+` FROM targets WHERE id=$1`, string(id)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (s *Store) Update(ctx context.Context, t *domain.Target) error {
+	httpOpts, err := marshalJSON(t.HTTP)
+	if err != nil {
+		return fmt.Errorf("marshal http options: %w", err)
+	}
+	latency, err := marshalJSON(t.Latency)
+	if err != nil {
+		return fmt.Errorf("marshal latency threshold: %w", err)
+	}
+	labels, err := marshalLabels(t.Labels)
+	if err != nil {
+		return fmt.Errorf("marshal labels: %w", err)
+	}
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE targets
+		    SET url=$2, http=$3, interval_ms=$4, timeout_ms=$5, group_name=$6, down_after=$7, up_after=$8, latency=$9, labels=$10, status_page=$11
+		  WHERE id=$1`,
+		string(t.ID), t.URL, httpOpts, t.IntervalMS, t.TimeoutMS, t.Group, t.DownAfter, t.UpAfter, latency, labels, t.StatusPage,
+	)
+	if err != nil {
+		return fmt.Errorf("update target: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+// Delete relies on ON DELETE CASCADE for results and incidents; alert state
+// has no foreign key and is removed explicitly.
+func (s *Store) Delete(ctx context.Context, id domain.TargetID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `DELETE FROM targets WHERE id=$1`, string(id))
+	if err != nil {
+		return fmt.Errorf("delete target: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM alerts WHERE target_id=$1`, string(id)); err != nil {
+		return fmt.Errorf("delete alerts: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *Store) SetPaused(ctx context.Context, id domain.TargetID, paused bool) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE targets SET paused=$2 WHERE id=$1`, string(id), paused)
+	if err != nil {
+		return fmt.Errorf("pause target: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+const targetCols = `id, url, created_at, http, interval_ms, timeout_ms, paused, group_name, down_after, up_after, latency, labels, status_page`
+
+func scanTarget(row pgx.Row) (*domain.Target, error) {
+	var (
+		t          domain.Target
+		id         string
+		httpRaw    []byte
+		latencyRaw []byte
+		labelsRaw  []byte
+	)
+	if err := row.Scan(&id, &t.URL, &t.CreatedAt, &httpRaw, &t.IntervalMS, &t.TimeoutMS, &t.Paused, &t.Group, &t.DownAfter, &t.UpAfter, &latencyRaw, &labelsRaw, &t.StatusPage); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan target: %w", err)
+	}
+	t.ID = domain.TargetID(id)
+	if err := unmarshalJSON(httpRaw, &t.HTTP); err != nil {
+		return nil, fmt.Errorf("decode http options: %w", err)
+	}
+	if err := unmarshalJSON(latencyRaw, &t.Latency); err != nil {
+		return nil, fmt.Errorf("decode latency threshold: %w", err)
+	}
+	if len(labelsRaw) > 0 {
+		if err := json.Unmarshal(labelsRaw, &t.Labels); err != nil {
+			return nil, fmt.Errorf("decode labels: %w", err)
+		}
+	}
+	return &t, nil
+}
+
 // ---- ResultStore ----
 
 func (s *Store) Append(ctx context.Context, cr *domain.CheckResult) error {
@@ -96,12 +203,16 @@ func (s *Store) Append(ctx context.Context, cr *domain.CheckResult) error {
 	if cr.HTTPStatus != 0 {
 		statusPtr = &cr.HTTPStatus
 	}
-	_, err := s.pool.Exec(ctx,
+	cert, err := marshalJSON(cr.Cert)
+	if err != nil {
+		return fmt.Errorf("marshal cert: %w", err)
+	}
+	_, err = s.pool.Exec(ctx,
 		`INSERT INTO results
-		   (target_id, up, http_status, latency_ms, reason, checked_at)
+		   (target_id, up, http_status, latency_ms, reason, checked_at, degraded, cert)
 		 VALUES
-		   ($1, $2, $3, $4, $5, $6)`,
-		string(cr.TargetID), cr.Up, statusPtr, cr.LatencyMS, cr.Reason, cr.CheckedAt,
+		   ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		string(cr.TargetID), cr.Up, statusPtr, cr.LatencyMS, cr.Reason, cr.CheckedAt, cr.Degraded, cert,
 	)
 	if err != nil {
 		return fmt.Errorf("insert result: %w", err)
@@ -118,7 +229,9 @@ SELECT DISTINCT ON (r.target_id)
        r.http_status,
        r.latency_ms,
        r.reason,
-       r.checked_at
+       r.checked_at,
+       r.degraded,
+       r.cert
   FROM results r
   JOIN targets t ON t.id = r.target_id
  ORDER BY r.target_id, r.checked_at DESC`)
@@ -137,10 +250,16 @@ SELECT DISTINCT ON (r.target_id)
 			latency   float64
 			reason    string
 			checkedAt time.Time
+			degraded  bool
+			certRaw   []byte
 		)
-		if err := rows.Scan(&targetID, &url, &up, &httpNull, &latency, &reason, &checkedAt); err != nil {
+		if err := rows.Scan(&targetID, &url, &up, &httpNull, &latency, &reason, &checkedAt, &degraded, &certRaw); err != nil {
 			return nil, fmt.Errorf("scan latest: %w", err)
 		}
+		var cert *domain.CertInfo
+		if err := unmarshalJSON(certRaw, &cert); err != nil {
+			return nil, fmt.Errorf("decode cert: %w", err)
+		}
 
 		// Build pointers with per-row copies
 		var httpStatusPtr *int
@@ -158,11 +277,42 @@ SELECT DISTINCT ON (r.target_id)
 			LatencyMS:  &lat, // repo.LatestRow expects *float64
 			Reason:     reason,
 			CheckedAt:  checkedAt,
+			Degraded:   degraded,
+			Cert:       cert,
 		})
 	}
 	return out, rows.Err()
 }
 
+// marshalJSON encodes v for a JSONB column; a nil pointer stays NULL.
+func marshalJSON[T any](v *T) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// marshalLabels stores no labels as NULL.
+func marshalLabels(l map[string]string) ([]byte, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// unmarshalJSON decodes a nullable JSONB column into *dst, leaving it nil for NULL.
+func unmarshalJSON[T any](b []byte, dst **T) error {
+	if len(b) == 0 {
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*dst = &v
+	return nil
+}
+
 // ID format similar to memory store: 20060102Thhmmss.nnnnnnnnn
 func makeID() string {
 	now := time.Now().UTC()
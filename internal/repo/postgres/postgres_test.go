@@ -32,6 +32,19 @@ CREATE TABLE IF NOT EXISTS results (
   checked_at  TIMESTAMPTZ NOT NULL
 );
 
+ALTER TABLE results ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE results ADD COLUMN IF NOT EXISTS cert     JSONB NULL;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS http     JSONB NULL;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS interval_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS timeout_ms  INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS paused      BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS group_name  TEXT NOT NULL DEFAULT '';
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS down_after  INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS up_after    INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS latency     JSONB NULL;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS labels      JSONB NULL;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS status_page BOOLEAN NOT NULL DEFAULT false;
+
 CREATE INDEX IF NOT EXISTS idx_results_target_time ON results (target_id, checked_at DESC);
 CREATE INDEX IF NOT EXISTS idx_results_checked_at   ON results (checked_at DESC);
 `
@@ -145,4 +158,18 @@ func TestPostgresStore_Add_List_Append_Latest(t *testing.T) {
 	if row.Reason == "" {
 		t.Fatalf("expected Reason to be set")
 	}
+
+	// History
+	page, err := store.History(ctx, repo.HistoryQuery{TargetID: string(tgt.ID), Limit: 10})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(page.Results) != 1 || page.Next != nil || page.Results[0].ID == 0 {
+		t.Fatalf("unexpected history page: %+v", page)
+	}
+	// an empty page encodes as [] rather than null
+	page, err = store.History(ctx, repo.HistoryQuery{TargetID: string(tgt.ID), From: time.Now().Add(time.Hour)})
+	if err != nil || page.Results == nil || len(page.Results) != 0 {
+		t.Fatalf("want empty non-nil results, got %+v err=%v", page, err)
+	}
 }
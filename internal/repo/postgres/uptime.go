@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+func (s *Store) Uptime(ctx context.Context, targetID string, from, to time.Time) (domain.UptimeReport, error) {
+	seed, samples, err := s.samples(ctx, targetID, from, to)
+	if err != nil {
+		return domain.UptimeReport{}, err
+	}
+	return repo.ComputeUptime(targetID, seed, samples, from, to), nil
+}
+
+func (s *Store) DailyUptime(ctx context.Context, targetID string, from time.Time, days int) ([]domain.UptimeReport, error) {
+	seed, samples, err := s.samples(ctx, targetID, from, from.Add(time.Duration(days)*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	return repo.ComputeDaily(targetID, seed, samples, from, days), nil
+}
+
+// samples loads the target's results in [from, to), oldest first, and the
+// last one before from.
+func (s *Store) samples(ctx context.Context, targetID string, from, to time.Time) (*repo.Sample, []repo.Sample, error) {
+	var seed *repo.Sample
+	var (
+		up      bool
+		latency sql.NullFloat64
+		at      time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+SELECT up, latency_ms, checked_at
+  FROM results
+ WHERE target_id = $1 AND checked_at < $2
+ ORDER BY checked_at DESC, id DESC
+ LIMIT 1`, targetID, from).Scan(&up, &latency, &at)
+	switch {
+	case err == nil:
+		seed = &repo.Sample{Up: up, LatencyMS: latency.Float64, CheckedAt: at}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, nil, fmt.Errorf("uptime seed: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+SELECT up, latency_ms, checked_at
+  FROM results
+ WHERE target_id = $1 AND checked_at >= $2 AND checked_at < $3
+ ORDER BY checked_at, id`, targetID, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("uptime: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []repo.Sample
+	for rows.Next() {
+		if err := rows.Scan(&up, &latency, &at); err != nil {
+			return nil, nil, fmt.Errorf("scan uptime: %w", err)
+		}
+		samples = append(samples, repo.Sample{Up: up, LatencyMS: latency.Float64, CheckedAt: at})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return seed, samples, nil
+}
@@ -2,19 +2,53 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/hamed0406/uptimechecker/internal/domain"
 )
 
+// ErrNotFound is returned when the addressed record does not exist.
+var ErrNotFound = errors.New("not found")
+
 type TargetStore interface {
 	Add(ctx context.Context, t *domain.Target) error
 	List(ctx context.Context) ([]*domain.Target, error)
+	// Find returns nil, nil if there's no target with that ID.
+	Find(ctx context.Context, id domain.TargetID) (*domain.Target, error)
+	// Update replaces the target's URL, group, check and alert settings (not ID/CreatedAt/Paused).
+	Update(ctx context.Context, t *domain.Target) error
+	// Delete removes the target together with its results, incidents and alert state.
+	Delete(ctx context.Context, id domain.TargetID) error
+	SetPaused(ctx context.Context, id domain.TargetID, paused bool) error
 }
 
 type ResultStore interface {
 	Append(ctx context.Context, r *domain.CheckResult) error
 	Latest(ctx context.Context) ([]LatestRow, error)
+	// History returns one page of a target's results, newest first.
+	History(ctx context.Context, q HistoryQuery) (HistoryPage, error)
+	// Uptime reports availability for a target over [from, to) (see ComputeUptime).
+	Uptime(ctx context.Context, targetID string, from, to time.Time) (domain.UptimeReport, error)
+	// DailyUptime reports availability for each of days consecutive 24h
+	// windows starting at from (see ComputeDaily); used for uptime bars.
+	DailyUptime(ctx context.Context, targetID string, from time.Time, days int) ([]domain.UptimeReport, error)
+}
+
+// HistoryQuery selects results for one target. Zero From/To leave that end
+// of the range open; From is inclusive, To exclusive. After continues from a
+// previous page's Next cursor.
+type HistoryQuery struct {
+	TargetID string
+	From     time.Time
+	To       time.Time
+	Limit    int
+	After    *Cursor
+}
+
+type HistoryPage struct {
+	Results []domain.Result
+	Next    *Cursor // nil when there are no more rows
 }
 
 type LatestRow struct {
@@ -25,4 +59,6 @@ type LatestRow struct {
 	LatencyMS  *float64
 	Reason     string
 	CheckedAt  time.Time
+	Degraded   bool
+	Cert       *domain.CertInfo
 }
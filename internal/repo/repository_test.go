@@ -13,8 +13,12 @@ import (
 func TestInterfaceSatisfaction(t *testing.T) {
 	var _ repo.TargetStore = memory.New()
 	var _ repo.ResultStore = memory.New()
+	var _ repo.AlertStore = memory.New()
+	var _ repo.IncidentStore = memory.New()
 
 	// Postgres store types compile against the interfaces, too.
 	var _ repo.TargetStore = (*pg.Store)(nil)
 	var _ repo.ResultStore = (*pg.Store)(nil)
+	var _ repo.AlertStore = (*pg.Store)(nil)
+	var _ repo.IncidentStore = (*pg.Store)(nil)
 }
@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+// Sample is the minimal view of a result needed for uptime reports.
+type Sample struct {
+	Up        bool
+	LatencyMS float64
+	CheckedAt time.Time
+}
+
+// ComputeUptime builds an UptimeReport from samples in [from, to), sorted
+// oldest first. seed is the last sample before from (nil if none) and gives
+// the state the window starts in. Both stores call this so the numbers match.
+//
+// Each sample's state is assumed to hold until the next sample; time before
+// the first known state is left out of the availability ratio. An outage
+// already in progress at from counts as one outage starting at from.
+func ComputeUptime(targetID string, seed *Sample, samples []Sample, from, to time.Time) domain.UptimeReport {
+	rep := domain.UptimeReport{
+		TargetID: domain.TargetID(targetID),
+		From:     from,
+		To:       to,
+		Checks:   len(samples),
+	}
+
+	var (
+		known    time.Duration // time covered by some state
+		down     time.Duration
+		state    *bool
+		since    time.Time // start of the current state
+		outStart time.Time // start of the current outage, if down
+		repairs  []time.Duration
+		lats     []float64
+	)
+	if seed != nil {
+		up := seed.Up
+		state, since = &up, from
+		if !up {
+			rep.Outages++
+			outStart = from
+		}
+	}
+
+	advance := func(until time.Time) {
+		if state == nil || !until.After(since) {
+			return
+		}
+		d := until.Sub(since)
+		known += d
+		if !*state {
+			down += d
+		}
+		since = until
+	}
+
+	for _, s := range samples {
+		advance(s.CheckedAt)
+		switch {
+		case state == nil:
+			if !s.Up {
+				rep.Outages++
+				outStart = s.CheckedAt
+			}
+		case *state && !s.Up:
+			rep.Outages++
+			outStart = s.CheckedAt
+		case !*state && s.Up:
+			repairs = append(repairs, s.CheckedAt.Sub(outStart))
+		}
+		up := s.Up
+		state, since = &up, s.CheckedAt
+		if s.Up && s.LatencyMS > 0 {
+			lats = append(lats, s.LatencyMS)
+		}
+	}
+
+	end := to
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+	advance(end)
+
+	rep.DowntimeSec = down.Seconds()
+	rep.MonitoredSec = known.Seconds()
+	if known > 0 {
+		pct := 100 * (1 - float64(down)/float64(known))
+		rep.UptimePct = &pct
+	}
+	if len(repairs) > 0 {
+		var sum time.Duration
+		for _, d := range repairs {
+			sum += d
+		}
+		mttr := (sum / time.Duration(len(repairs))).Seconds()
+		rep.MTTRSec = &mttr
+	}
+	if len(lats) > 0 {
+		sort.Float64s(lats)
+		rep.LatencyP50 = Percentile(lats, 50)
+		rep.LatencyP95 = Percentile(lats, 95)
+		rep.LatencyP99 = Percentile(lats, 99)
+	}
+	return rep
+}
+
+// Percentile returns the nearest-rank percentile of sorted values.
+func Percentile(sorted []float64, p float64) *float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	v := sorted[rank-1]
+	return &v
+}
+
+// ComputeDaily splits [from, from+days*24h) into consecutive 24h windows and
+// runs ComputeUptime on each, carrying the last state across day boundaries.
+// samples must be sorted oldest first.
+func ComputeDaily(targetID string, seed *Sample, samples []Sample, from time.Time, days int) []domain.UptimeReport {
+	out := make([]domain.UptimeReport, 0, days)
+	i := 0
+	for d := 0; d < days; d++ {
+		start := from.Add(time.Duration(d) * 24 * time.Hour)
+		end := start.Add(24 * time.Hour)
+		j := i
+		for j < len(samples) && samples[j].CheckedAt.Before(end) {
+			j++
+		}
+		out = append(out, ComputeUptime(targetID, seed, samples[i:j], start, end))
+		if j > i {
+			last := samples[j-1]
+			seed = &last
+		}
+		i = j
+	}
+	return out
+}
+
+// CombineUptime is the availability over consecutive reports, e.g. the days
+// from ComputeDaily, weighted by how long each was monitored; nil if none
+// was.
+func CombineUptime(reports []domain.UptimeReport) *float64 {
+	var known, down float64
+	for _, r := range reports {
+		known += r.MonitoredSec
+		down += r.DowntimeSec
+	}
+	if known == 0 {
+		return nil
+	}
+	pct := 100 * (1 - down/known)
+	return &pct
+}
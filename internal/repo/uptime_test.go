@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeUptime(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Minute)
+	at := func(m int) time.Time { return from.Add(time.Duration(m) * time.Minute) }
+
+	// seed is DOWN, recovers at 10m; second outage 50m–60m; UP until the end.
+	seed := &Sample{Up: false, CheckedAt: from.Add(-time.Minute)}
+	samples := []Sample{
+		{Up: true, LatencyMS: 100, CheckedAt: at(10)},
+		{Up: true, LatencyMS: 200, CheckedAt: at(30)},
+		{Up: false, CheckedAt: at(50)},
+		{Up: true, LatencyMS: 300, CheckedAt: at(60)},
+		{Up: true, LatencyMS: 400, CheckedAt: at(80)},
+	}
+	rep := ComputeUptime("A", seed, samples, from, to)
+
+	if rep.Checks != 5 {
+		t.Fatalf("checks: got %d", rep.Checks)
+	}
+	if rep.Outages != 2 {
+		t.Fatalf("outages: got %d", rep.Outages)
+	}
+	if rep.DowntimeSec != 20*60 {
+		t.Fatalf("downtime: got %v", rep.DowntimeSec)
+	}
+	if rep.UptimePct == nil || *rep.UptimePct != 80 {
+		t.Fatalf("uptime: got %v", rep.UptimePct)
+	}
+	if rep.MTTRSec == nil || *rep.MTTRSec != 10*60 {
+		t.Fatalf("mttr: got %v", rep.MTTRSec)
+	}
+	if *rep.LatencyP50 != 200 || *rep.LatencyP95 != 400 || *rep.LatencyP99 != 400 {
+		t.Fatalf("latency: p50=%v p95=%v p99=%v", *rep.LatencyP50, *rep.LatencyP95, *rep.LatencyP99)
+	}
+}
+
+func TestComputeUptime_NoData(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rep := ComputeUptime("A", nil, nil, from, from.Add(time.Hour))
+	if rep.UptimePct != nil || rep.MTTRSec != nil || rep.LatencyP50 != nil || rep.Outages != 0 {
+		t.Fatalf("want empty report, got %+v", rep)
+	}
+}
+
+func TestComputeUptime_UnknownLeadIn(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// first check half way through: only the second half counts
+	samples := []Sample{
+		{Up: true, CheckedAt: from.Add(30 * time.Minute)},
+		{Up: false, CheckedAt: from.Add(45 * time.Minute)},
+	}
+	rep := ComputeUptime("A", nil, samples, from, from.Add(time.Hour))
+	if rep.UptimePct == nil || *rep.UptimePct != 50 || rep.Outages != 1 || rep.MTTRSec != nil {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+}
+
+func TestComputeDaily_CarriesStateAcrossDays(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	// Down from 18:00 on day 0 until 06:00 on day 1. Day 0 starts with no
+	// known state; day 2 has no checks but inherits UP from day 1.
+	samples := []Sample{
+		{Up: true, CheckedAt: from.Add(12 * time.Hour)},
+		{Up: false, CheckedAt: from.Add(18 * time.Hour)},
+		{Up: true, CheckedAt: from.Add(day + 6*time.Hour)},
+	}
+	reps := ComputeDaily("A", nil, samples, from, 3)
+	if len(reps) != 3 {
+		t.Fatalf("days: got %d", len(reps))
+	}
+	if reps[0].Checks != 2 || reps[0].UptimePct == nil || *reps[0].UptimePct != 50 {
+		t.Fatalf("day 0: checks=%d uptime=%v", reps[0].Checks, reps[0].UptimePct)
+	}
+	if reps[1].Checks != 1 || reps[1].UptimePct == nil || *reps[1].UptimePct != 75 || reps[1].Outages != 1 {
+		t.Fatalf("day 1: checks=%d uptime=%v outages=%d", reps[1].Checks, reps[1].UptimePct, reps[1].Outages)
+	}
+	if reps[2].Checks != 0 || reps[2].UptimePct == nil || *reps[2].UptimePct != 100 {
+		t.Fatalf("day 2: checks=%d uptime=%v", reps[2].Checks, reps[2].UptimePct)
+	}
+	if !reps[2].From.Equal(from.Add(2*day)) || !reps[2].To.Equal(from.Add(3*day)) {
+		t.Fatalf("day 2 window: %v – %v", reps[2].From, reps[2].To)
+	}
+
+	// The days combine to the same figure as one report over the window:
+	// 12h down out of 60h monitored.
+	whole := ComputeUptime("A", nil, samples, from, from.Add(3*day))
+	if got := CombineUptime(reps); got == nil || *got != 80 || *whole.UptimePct != 80 {
+		t.Fatalf("combined uptime = %v, whole window = %v", got, whole.UptimePct)
+	}
+	if CombineUptime(ComputeDaily("A", nil, nil, from, 3)) != nil {
+		t.Fatal("no data should combine to nil")
+	}
+}
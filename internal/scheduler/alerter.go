@@ -3,8 +3,12 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/metrics"
+	"github.com/hamed0406/uptimechecker/internal/notify"
 	"github.com/hamed0406/uptimechecker/internal/repo"
 )
 
@@ -12,23 +16,45 @@ type AlerterConfig struct {
 	AlertOnRecovery bool
 	Cooldown        time.Duration
 	PollInterval    time.Duration
+	// DownAfter / UpAfter are the default number of consecutive failed /
+	// successful checks needed before a state change is alerted (<= 1 means
+	// the latest result alone decides). Targets may override them.
+	DownAfter int
+	UpAfter   int
+	// Flap detection (0 FlapThreshold disables): a target with at least
+	// FlapThreshold up/down transitions within FlapWindow is flapping. One
+	// notice is sent and DOWN/RECOVERED alerts are held back until the count
+	// drops to FlapSettle or below; the gap between the two thresholds keeps
+	// the flapping state itself from flapping (as in Nagios).
+	FlapWindow    time.Duration
+	FlapThreshold int
+	FlapSettle    int
 }
 
+// flapMaxSamples caps the results read per target for flap detection.
+const flapMaxSamples = 1000
+
 type Alerter struct {
 	results  repo.ResultStore
 	alertDB  repo.AlertStore
-	notifier interface {
-		Send(context.Context, string, string) error
-	}
-	cfg AlerterConfig
+	notifier notify.Notifier
+	cfg      AlerterConfig
+
+	// Metrics is optional; set after construction.
+	Metrics *metrics.Metrics
+	// Targets is optional; when set, per-target DownAfter/UpAfter apply.
+	Targets repo.TargetStore
+	// Incidents is optional; when set, events carry the related incident.
+	Incidents repo.IncidentStore
+	// Escalation is optional; with Incidents, unacknowledged outages are
+	// escalated through its tiers.
+	Escalation notify.Escalator
 }
 
 func NewAlerter(
 	results repo.ResultStore,
 	alertDB repo.AlertStore,
-	notifier interface {
-		Send(context.Context, string, string) error
-	},
+	notifier notify.Notifier,
 	cfg AlerterConfig,
 ) *Alerter {
 	return &Alerter{
@@ -59,17 +85,31 @@ func (a *Alerter) Run(ctx context.Context) error {
 func (a *Alerter) scanOnce(ctx context.Context) error {
 	rows, err := a.results.Latest(ctx)
 	if err != nil {
+		a.Metrics.StoreError("latest")
 		return err
 	}
 
 	now := time.Now()
+	targets := a.targetsByID(ctx)
 
 	for _, r := range rows {
 		rec, _ := a.alertDB.Get(ctx, r.TargetID)
 
+		// While flapping, don't notify. The recorded state is left as last
+		// announced, so a change is alerted as usual once the target settles.
+		if a.flapping(ctx, r, rec, targets[r.TargetID], now) {
+			continue
+		}
+
 		// Has the up/down state changed compared to what we last recorded?
 		stateChanged := rec == nil || rec.LastState != r.Up
 
+		// A new state only counts once enough consecutive checks agree.
+		streak := streakNeeded(targets[r.TargetID], r.Up, a.cfg.DownAfter, a.cfg.UpAfter)
+		if stateChanged && !a.confirmed(ctx, r, streak) {
+			continue
+		}
+
 		// Cooldown only matters for DOWN alerts (suppresses noisy repeats).
 		cooled := true
 		if rec != nil && rec.LastSentAt != nil {
@@ -79,8 +119,10 @@ func (a *Alerter) scanOnce(ctx context.Context) error {
 		// Decide which alert (if any) should be sent.
 		downAlert := stateChanged && !r.Up && cooled
 		recoveryAlert := stateChanged && r.Up && a.cfg.AlertOnRecovery // bypass cooldown
+		// Without recovery alerts, pages opened by the DOWN must still close.
+		resolveOnly := stateChanged && r.Up && !a.cfg.AlertOnRecovery && rec != nil && !rec.LastState
 
-		if downAlert || recoveryAlert {
+		if downAlert || recoveryAlert || resolveOnly {
 			// Title by state
 			title := "🔴 Target DOWN"
 			if r.Up {
@@ -104,10 +146,26 @@ func (a *Alerter) scanOnce(ctx context.Context) error {
 				"URL: %s\nHTTP: %s\nLatency: %s\nReason: %s\nChecked: %s",
 				r.URL, httpTxt, latencyTxt, r.Reason, r.CheckedAt.Format(time.RFC3339),
 			)
+			if streak > 1 {
+				text += fmt.Sprintf("\nConfirmed by: %d consecutive checks", streak)
+			}
 
-			// Best‑effort send and record the send time
-			_ = a.notifier.Send(ctx, title, text)
-			_ = a.alertDB.Set(ctx, r.TargetID, r.Up, now)
+			// Record the new state only once the alert is handed off, so a
+			// failed send is retried on the next scan instead of being lost.
+			kind := notify.KindDown
+			if r.Up {
+				kind = notify.KindRecovered
+			}
+			ev := a.event(ctx, kind, title, text, r, rec, targets[r.TargetID])
+			ev.ResolveOnly = resolveOnly
+			if err := a.deliver(ctx, ev); err != nil {
+				continue
+			}
+			sentAt := now
+			if resolveOnly {
+				sentAt = time.Time{}
+			}
+			_ = a.alertDB.Set(ctx, r.TargetID, r.Up, sentAt)
 			continue
 		}
 
@@ -118,5 +176,282 @@ func (a *Alerter) scanOnce(ctx context.Context) error {
 		}
 	}
 
+	// Certificate expiry is announced separately from up/down, once per certificate.
+	for _, r := range rows {
+		if r.Cert == nil || !r.Cert.Expiring {
+			continue
+		}
+		rec, _ := a.alertDB.Get(ctx, r.TargetID)
+		if rec != nil && rec.CertNotifiedFor != nil && rec.CertNotifiedFor.Equal(r.Cert.NotAfter) {
+			continue
+		}
+
+		days := int(time.Until(r.Cert.NotAfter).Hours() / 24)
+		text := fmt.Sprintf(
+			"URL: %s\nExpires: %s (%d days)\nIssuer: %s\nChecked: %s",
+			r.URL, r.Cert.NotAfter.Format(time.RFC3339), days, r.Cert.Issuer, r.CheckedAt.Format(time.RFC3339),
+		)
+		if err := a.send(ctx, notify.KindCertExpiring, "🟠 Certificate EXPIRING", text, r, rec, targets[r.TargetID]); err != nil {
+			continue
+		}
+		_ = a.alertDB.SetCertNotified(ctx, r.TargetID, r.Cert.NotAfter)
+	}
+
+	// Slow responses are a separate "degraded" state, only judged while up.
+	for _, r := range rows {
+		if t := targets[r.TargetID]; t != nil && r.Up {
+			a.checkLatency(ctx, r, t)
+		}
+	}
+
+	a.escalate(ctx, rows, targets, now)
 	return nil
 }
+
+// targetsByID loads targets for per-target settings; nil if unavailable.
+func (a *Alerter) targetsByID(ctx context.Context) map[string]*domain.Target {
+	if a.Targets == nil {
+		return nil
+	}
+	ts, err := a.Targets.List(ctx)
+	if err != nil {
+		a.Metrics.StoreError("list_targets")
+		return nil
+	}
+	out := make(map[string]*domain.Target, len(ts))
+	for _, t := range ts {
+		out[string(t.ID)] = t
+	}
+	return out
+}
+
+// streakNeeded is how many consecutive results in state up are required
+// before that state is believed: t's own down_after/up_after, else the
+// defaults.
+func streakNeeded(t *domain.Target, up bool, downAfter, upAfter int) int {
+	n := downAfter
+	if up {
+		n = upAfter
+	}
+	if t != nil {
+		if up && t.UpAfter > 0 {
+			n = t.UpAfter
+		}
+		if !up && t.DownAfter > 0 {
+			n = t.DownAfter
+		}
+	}
+	return n
+}
+
+// confirmed reports whether the last n stored results of r's target all
+// share r's state.
+func (a *Alerter) confirmed(ctx context.Context, r repo.LatestRow, n int) bool {
+	if n <= 1 {
+		return true
+	}
+	page, err := a.results.History(ctx, repo.HistoryQuery{TargetID: r.TargetID, Limit: n})
+	if err != nil {
+		a.Metrics.StoreError("history")
+		return false
+	}
+	if len(page.Results) < n {
+		return false
+	}
+	for _, res := range page.Results {
+		if res.Up != r.Up {
+			return false
+		}
+	}
+	return true
+}
+
+// flapping updates the target's flap state from its recent transitions,
+// sending a notice when it starts or stops flapping, and reports whether
+// DOWN/RECOVERED alerts should be suppressed this round. Once it settles
+// they are not: settling in another state than the one announced before the
+// flapping (e.g. down) raises the DOWN or RECOVERED alert it held back.
+func (a *Alerter) flapping(ctx context.Context, r repo.LatestRow, rec *repo.AlertRecord, t *domain.Target, now time.Time) bool {
+	if a.cfg.FlapThreshold <= 0 || a.cfg.FlapWindow <= 0 {
+		return false
+	}
+	was := rec != nil && rec.Flapping
+	n, err := a.transitions(ctx, r.TargetID, now.Add(-a.cfg.FlapWindow))
+	if err != nil {
+		a.Metrics.StoreError("history")
+		return was
+	}
+
+	switch {
+	case !was && n >= a.cfg.FlapThreshold:
+		if rec == nil {
+			_ = a.alertDB.Set(ctx, r.TargetID, r.Up, time.Time{})
+		}
+		_ = a.alertDB.SetFlapping(ctx, r.TargetID, true)
+		text := fmt.Sprintf(
+			"URL: %s\nTransitions: %d in %s\nDOWN/RECOVERED alerts are paused until it settles.\nChecked: %s",
+			r.URL, n, a.cfg.FlapWindow, r.CheckedAt.Format(time.RFC3339),
+		)
+		a.send(ctx, notify.KindFlapping, "🟡 Target FLAPPING", text, r, rec, t)
+		return true
+	case was && n > a.cfg.FlapSettle:
+		return true
+	case was:
+		_ = a.alertDB.SetFlapping(ctx, r.TargetID, false)
+		state := "DOWN"
+		if r.Up {
+			state = "UP"
+		}
+		text := fmt.Sprintf(
+			"URL: %s\nCurrent state: %s\nChecked: %s",
+			r.URL, state, r.CheckedAt.Format(time.RFC3339),
+		)
+		a.send(ctx, notify.KindFlappingEnded, "🔵 Target STABLE", text, r, rec, t)
+		return false
+	}
+	return false
+}
+
+// transitions counts up/down changes between consecutive results since from.
+func (a *Alerter) transitions(ctx context.Context, targetID string, from time.Time) (int, error) {
+	page, err := a.results.History(ctx, repo.HistoryQuery{TargetID: targetID, From: from, Limit: flapMaxSamples})
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := 1; i < len(page.Results); i++ {
+		if page.Results[i].Up != page.Results[i-1].Up {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// checkLatency compares the target's recent latency percentile with its
+// threshold and notifies when it becomes or stops being degraded. Removing
+// the threshold of a degraded target ends the degraded state.
+func (a *Alerter) checkLatency(ctx context.Context, r repo.LatestRow, t *domain.Target) {
+	th := t.Latency
+	rec, _ := a.alertDB.Get(ctx, r.TargetID)
+	if rec == nil || rec.Flapping {
+		return // wait for the up/down state to be recorded
+	}
+	var slow bool
+	var text string
+	if th == nil {
+		if !rec.Degraded {
+			return
+		}
+		text = fmt.Sprintf("URL: %s\nLatency: threshold removed\nChecked: %s", r.URL, r.CheckedAt.Format(time.RFC3339))
+	} else {
+		p, ok := a.latencyPercentile(ctx, r.TargetID, th)
+		if !ok {
+			return
+		}
+		slow = p > th.MaxMS
+		if slow == rec.Degraded {
+			return
+		}
+		text = fmt.Sprintf(
+			"URL: %s\nLatency: p%g of last %d checks is %.0f ms (threshold %.0f ms)\nChecked: %s",
+			r.URL, th.Percentile, th.Samples, p, th.MaxMS, r.CheckedAt.Format(time.RFC3339),
+		)
+	}
+	kind, title := notify.KindDegraded, "🟠 Target DEGRADED"
+	if !slow {
+		kind, title = notify.KindDegradedRecovered, "🟢 Target latency OK"
+	}
+	ev := a.event(ctx, kind, title, text, r, rec, t)
+	ev.ResolveOnly = !slow && !a.cfg.AlertOnRecovery
+	if err := a.deliver(ctx, ev); err != nil {
+		return // keep the old state so the next scan retries
+	}
+	_ = a.alertDB.SetDegraded(ctx, r.TargetID, slow)
+}
+
+// latencyPercentile returns the threshold's percentile over the latencies of
+// the successful checks among the last th.Samples results. ok is false until
+// enough results exist.
+func (a *Alerter) latencyPercentile(ctx context.Context, targetID string, th *domain.LatencyThreshold) (float64, bool) {
+	page, err := a.results.History(ctx, repo.HistoryQuery{TargetID: targetID, Limit: th.Samples})
+	if err != nil {
+		a.Metrics.StoreError("history")
+		return 0, false
+	}
+	if len(page.Results) < th.Samples {
+		return 0, false
+	}
+	var lats []float64
+	for _, res := range page.Results {
+		if res.Up && res.LatencyMS != nil {
+			lats = append(lats, *res.LatencyMS)
+		}
+	}
+	if len(lats) == 0 {
+		return 0, false
+	}
+	sort.Float64s(lats)
+	return *repo.Percentile(lats, th.Percentile), true
+}
+
+// send delivers one alert about r, as a structured event to notifiers that
+// support it. rec is the alert record from before this alert (may be nil)
+// and t the target, if known.
+func (a *Alerter) send(ctx context.Context, kind, title, text string, r repo.LatestRow, rec *repo.AlertRecord, t *domain.Target) error {
+	return a.deliver(ctx, a.event(ctx, kind, title, text, r, rec, t))
+}
+
+// deliver sends ev and counts the attempt.
+func (a *Alerter) deliver(ctx context.Context, ev notify.Event) error {
+	err := notify.Deliver(ctx, a.notifier, ev)
+	a.Metrics.Notification(ev.Kind, err)
+	return err
+}
+
+// event builds the structured alert for r's target.
+func (a *Alerter) event(ctx context.Context, kind, title, text string, r repo.LatestRow, rec *repo.AlertRecord, t *domain.Target) notify.Event {
+	ev := notify.Event{
+		Kind:   kind,
+		Title:  title,
+		Text:   text,
+		Target: &domain.Target{ID: domain.TargetID(r.TargetID), URL: r.URL},
+		Result: &domain.Result{
+			TargetID:   domain.TargetID(r.TargetID),
+			Up:         r.Up,
+			HTTPStatus: r.HTTPStatus,
+			LatencyMS:  r.LatencyMS,
+			Reason:     r.Reason,
+			CheckedAt:  r.CheckedAt,
+			Degraded:   r.Degraded,
+		},
+		At: time.Now().UTC(),
+	}
+	if t != nil {
+		ev.Target = t.Redacted()
+	}
+	if rec != nil {
+		ev.PreviousState = "down"
+		if rec.LastState {
+			ev.PreviousState = "up"
+		}
+	}
+	ev.Incident = a.latestIncident(ctx, r.TargetID, ev.Resolves())
+	return ev
+}
+
+// latestIncident returns the target's open incident, or its most recent one
+// if closed is true (e.g. the outage a recovery ends).
+func (a *Alerter) latestIncident(ctx context.Context, targetID string, closed bool) *domain.Incident {
+	if a.Incidents == nil {
+		return nil
+	}
+	ins, err := a.Incidents.Incidents(ctx, repo.IncidentQuery{TargetID: targetID, Limit: 1})
+	if err != nil {
+		a.Metrics.StoreError("incidents")
+		return nil
+	}
+	if len(ins) == 0 || (!closed && !ins[0].Open()) {
+		return nil
+	}
+	return &ins[0]
+}
@@ -2,10 +2,18 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/notify"
 	"github.com/hamed0406/uptimechecker/internal/repo"
+	"github.com/hamed0406/uptimechecker/internal/repo/memory"
 )
 
 // ---- shared helpers ----
@@ -46,15 +54,49 @@ func (m *memAlerts) Set(ctx context.Context, targetID string, lastState bool, se
 	if !sentAt.IsZero() {
 		ts = &sentAt
 	}
-	m.m[targetID] = repo.AlertRecord{TargetID: targetID, LastState: lastState, LastSentAt: ts}
+	r := m.m[targetID]
+	r.TargetID, r.LastState, r.LastSentAt = targetID, lastState, ts
+	m.m[targetID] = r
+	return nil
+}
+func (m *memAlerts) SetCertNotified(ctx context.Context, targetID string, notAfter time.Time) error {
+	r, ok := m.m[targetID]
+	if !ok {
+		r = repo.AlertRecord{TargetID: targetID, LastState: true}
+	}
+	r.CertNotifiedFor = &notAfter
+	m.m[targetID] = r
+	return nil
+}
+func (m *memAlerts) SetFlapping(ctx context.Context, targetID string, flapping bool) error {
+	r, ok := m.m[targetID]
+	if !ok {
+		return nil
+	}
+	r.Flapping = flapping
+	m.m[targetID] = r
+	return nil
+}
+func (m *memAlerts) SetDegraded(ctx context.Context, targetID string, degraded bool) error {
+	r, ok := m.m[targetID]
+	if !ok {
+		return nil
+	}
+	r.Degraded = degraded
+	m.m[targetID] = r
 	return nil
 }
 
-type memNotifier struct{ n int }
+type memNotifier struct {
+	n      int
+	titles []string
+	err    error // returned by Send when set
+}
 
 func (m *memNotifier) Send(ctx context.Context, title, text string) error {
 	m.n++
-	return nil
+	m.titles = append(m.titles, title)
+	return m.err
 }
 
 // ---- tests ----
@@ -127,4 +169,347 @@ func TestAlerter_NoRecoveryIfDisabled(t *testing.T) {
 	}
 }
 
+func TestAlerter_FailedSendIsRetried(t *testing.T) {
+	results := &fakeResults{rows: []repo.LatestRow{row("A", "https://a", false, intp(500), 100)}}
+	alerts := &memAlerts{}
+	nt := &memNotifier{err: errors.New("slack is down")}
+	al := NewAlerter(results, alerts, nt, AlerterConfig{Cooldown: time.Hour})
+
+	if err := al.scanOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := alerts.m["A"]; ok {
+		t.Fatalf("failed alert must not be recorded as sent: %+v", alerts.m["A"])
+	}
+
+	nt.err = nil
+	if err := al.scanOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if nt.n != 2 || alerts.m["A"].LastSentAt == nil {
+		t.Fatalf("want the DOWN alert retried and recorded: sends=%d rec=%+v", nt.n, alerts.m["A"])
+	}
+}
+
+func TestAlerter_CertExpiringSentOncePerCert(t *testing.T) {
+	r := row("C", "https://c", true, intp(200), 40)
+	r.Degraded = true
+	r.Cert = &domain.CertInfo{NotAfter: time.Now().Add(72 * time.Hour), Issuer: "CN=Test CA", Expiring: true}
+	results := &fakeResults{rows: []repo.LatestRow{r}}
+	nt := &memNotifier{}
+	al := NewAlerter(results, &memAlerts{}, nt, AlerterConfig{Cooldown: time.Minute})
+
+	// UP with recovery disabled -> only the cert notice
+	if err := al.scanOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if nt.n != 1 || nt.titles[0] != "🟠 Certificate EXPIRING" {
+		t.Fatalf("want one cert notice, got %v", nt.titles)
+	}
+
+	// same cert again -> nothing new
+	if err := al.scanOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if nt.n != 1 {
+		t.Fatalf("want cert notice sent once, got %d", nt.n)
+	}
+
+	// renewed cert that is again inside the window -> new notice
+	renewed := *r.Cert
+	renewed.NotAfter = renewed.NotAfter.Add(24 * time.Hour)
+	results.rows[0].Cert = &renewed
+	if err := al.scanOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if nt.n != 2 {
+		t.Fatalf("want notice for new cert, got %d", nt.n)
+	}
+}
+
 func intp(i int) *int { return &i }
+
+// historyResults serves Latest from rows and History from hist (newest first).
+type historyResults struct {
+	fakeResults
+	hist []domain.Result
+}
+
+func (h *historyResults) History(ctx context.Context, q repo.HistoryQuery) (repo.HistoryPage, error) {
+	out := h.hist
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return repo.HistoryPage{Results: out}, nil
+}
+
+func results(ups ...bool) []domain.Result {
+	out := make([]domain.Result, len(ups))
+	for i, up := range ups {
+		out[i] = domain.Result{TargetID: "A", Up: up}
+	}
+	return out
+}
+
+func TestAlerter_DownAfterConsecutiveFailures(t *testing.T) {
+	rs := &historyResults{}
+	rs.rows = []repo.LatestRow{row("A", "https://a", false, nil, 0)}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: true}}}
+	n := &memNotifier{}
+	a := NewAlerter(rs, alerts, n, AlerterConfig{Cooldown: time.Minute, DownAfter: 3, UpAfter: 1})
+
+	// two failures after a success: a blip, not an outage yet
+	rs.hist = results(false, false, true)
+	_ = a.scanOnce(context.Background())
+	if n.n != 0 || !alerts.m["A"].LastState {
+		t.Fatalf("blip should not alert or flip state: sent=%d state=%v", n.n, alerts.m["A"].LastState)
+	}
+
+	rs.hist = results(false, false, false, true)
+	_ = a.scanOnce(context.Background())
+	if n.n != 1 || alerts.m["A"].LastState {
+		t.Fatalf("want DOWN after 3 failures: sent=%d state=%v", n.n, alerts.m["A"].LastState)
+	}
+}
+
+func TestAlerter_PerTargetUpAfterOverridesDefault(t *testing.T) {
+	rs := &historyResults{}
+	rs.rows = []repo.LatestRow{row("A", "https://a", true, nil, 10)}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: false}}}
+	n := &memNotifier{}
+	a := NewAlerter(rs, alerts, n, AlerterConfig{AlertOnRecovery: true, UpAfter: 1})
+	a.Targets = &staticTargets{t: []*domain.Target{{ID: "A", URL: "https://a", UpAfter: 2}}}
+
+	rs.hist = results(true, false)
+	_ = a.scanOnce(context.Background())
+	if n.n != 0 {
+		t.Fatalf("one success should not recover a target with up_after=2, sent=%d", n.n)
+	}
+
+	rs.hist = results(true, true, false)
+	_ = a.scanOnce(context.Background())
+	if n.n != 1 || n.titles[0] != "🟢 Target RECOVERED" {
+		t.Fatalf("want RECOVERED after 2 successes, got %v", n.titles)
+	}
+}
+
+func TestAlerter_FlappingSuppressesUntilSettled(t *testing.T) {
+	rs := &historyResults{}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: true}}}
+	n := &memNotifier{}
+	a := NewAlerter(rs, alerts, n, AlerterConfig{
+		AlertOnRecovery: true,
+		FlapWindow:      15 * time.Minute,
+		FlapThreshold:   5,
+		FlapSettle:      1,
+	})
+	scan := func(up bool, hist ...bool) {
+		rs.rows = []repo.LatestRow{row("A", "https://a", up, nil, 10)}
+		rs.hist = results(hist...)
+		_ = a.scanOnce(context.Background())
+	}
+
+	// 5 transitions: one FLAPPING notice instead of a DOWN
+	scan(false, false, true, false, true, false, true)
+	if n.n != 1 || n.titles[0] != "🟡 Target FLAPPING" || !alerts.m["A"].Flapping {
+		t.Fatalf("want one flapping notice, got %v", n.titles)
+	}
+	if !alerts.m["A"].LastState {
+		t.Fatal("the announced state must not change while flapping")
+	}
+
+	// still unstable: recovery is suppressed
+	scan(true, true, false, true, false, true)
+	if n.n != 1 {
+		t.Fatalf("alerts should be suppressed while flapping, got %v", n.titles)
+	}
+
+	// settled: one STABLE notice, then normal alerting resumes
+	scan(true, true, true, true)
+	if n.n != 2 || n.titles[1] != "🔵 Target STABLE" || alerts.m["A"].Flapping {
+		t.Fatalf("want stable notice, got %v", n.titles)
+	}
+	scan(false, false, true, true)
+	if n.n != 3 || n.titles[2] != "🔴 Target DOWN" {
+		t.Fatalf("want DOWN after settling, got %v", n.titles)
+	}
+}
+
+func TestAlerter_FlappingSettledDownAlertsDown(t *testing.T) {
+	rs := &historyResults{}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: true}}}
+	n := &memNotifier{}
+	a := NewAlerter(rs, alerts, n, AlerterConfig{
+		Cooldown:      time.Hour,
+		FlapWindow:    15 * time.Minute,
+		FlapThreshold: 5,
+		FlapSettle:    1,
+	})
+	scan := func(up bool, hist ...bool) {
+		rs.rows = []repo.LatestRow{row("A", "https://a", up, nil, 10)}
+		rs.hist = results(hist...)
+		_ = a.scanOnce(context.Background())
+	}
+
+	scan(false, false, true, false, true, false, true)
+	scan(false, false, false, false, false, true)
+	want := []string{"🟡 Target FLAPPING", "🔵 Target STABLE", "🔴 Target DOWN"}
+	if !reflect.DeepEqual(n.titles, want) {
+		t.Fatalf("got %v, want %v", n.titles, want)
+	}
+	if rec := alerts.m["A"]; rec.LastState || rec.LastSentAt == nil || rec.Flapping {
+		t.Fatalf("want DOWN recorded with a send time, got %+v", rec)
+	}
+
+	// the DOWN starts the cooldown as usual
+	scan(false, false, false, false)
+	if n.n != 3 {
+		t.Fatalf("want no further alerts, got %v", n.titles)
+	}
+}
+
+func TestAlerter_FlappingSettledDownPages(t *testing.T) {
+	var actions []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			EventAction string `json:"event_action"`
+			DedupKey    string `json:"dedup_key"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		actions = append(actions, body.EventAction+" "+body.DedupKey)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+	pd := notify.NewPagerDuty("key")
+	pd.URL = ts.URL
+
+	rs := &historyResults{}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: true}}}
+	a := NewAlerter(rs, alerts, pd, AlerterConfig{FlapWindow: 15 * time.Minute, FlapThreshold: 5, FlapSettle: 1})
+	a.Targets = &staticTargets{t: []*domain.Target{{ID: "A", URL: "https://a"}}}
+	scan := func(up bool, hist ...bool) {
+		rs.rows = []repo.LatestRow{row("A", "https://a", up, nil, 10)}
+		rs.hist = results(hist...)
+		_ = a.scanOnce(context.Background())
+	}
+
+	scan(false, false, true, false, true, false, true)
+	scan(false, false, false, false, false, true)
+	if want := []string{"trigger uptimechecker-A"}; !reflect.DeepEqual(actions, want) {
+		t.Fatalf("flapping settled down must page: got %v, want %v", actions, want)
+	}
+}
+
+func latencies(ms ...float64) []domain.Result {
+	out := make([]domain.Result, len(ms))
+	for i := range ms {
+		out[i] = domain.Result{TargetID: "A", Up: true, LatencyMS: &ms[i]}
+	}
+	return out
+}
+
+func TestAlerter_LatencyDegradedAndRecovered(t *testing.T) {
+	rs := &historyResults{}
+	rs.rows = []repo.LatestRow{row("A", "https://a", true, nil, 900)}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: true}}}
+	n := &memNotifier{}
+	a := NewAlerter(rs, alerts, n, AlerterConfig{AlertOnRecovery: true})
+	a.Targets = &staticTargets{t: []*domain.Target{{
+		ID: "A", URL: "https://a",
+		Latency: &domain.LatencyThreshold{Percentile: 95, Samples: 5, MaxMS: 800},
+	}}}
+
+	// not enough samples yet
+	rs.hist = latencies(900, 900)
+	_ = a.scanOnce(context.Background())
+	if n.n != 0 {
+		t.Fatalf("want no alert before 5 samples, got %v", n.titles)
+	}
+
+	rs.hist = latencies(900, 100, 100, 100, 100)
+	_ = a.scanOnce(context.Background())
+	_ = a.scanOnce(context.Background())
+	if n.n != 1 || n.titles[0] != "🟠 Target DEGRADED" || !alerts.m["A"].Degraded {
+		t.Fatalf("want one DEGRADED alert, got %v", n.titles)
+	}
+	if !alerts.m["A"].LastState {
+		t.Fatal("latency must not change the up/down state")
+	}
+
+	rs.hist = latencies(100, 120, 90, 100, 110)
+	_ = a.scanOnce(context.Background())
+	if n.n != 2 || n.titles[1] != "🟢 Target latency OK" || alerts.m["A"].Degraded {
+		t.Fatalf("want latency OK alert, got %v", n.titles)
+	}
+}
+
+func TestAlerter_LatencyThresholdRemovedWhileDegraded(t *testing.T) {
+	rs := &historyResults{}
+	rs.rows = []repo.LatestRow{row("A", "https://a", true, nil, 900)}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: true, Degraded: true}}}
+	n := &memNotifier{}
+	a := NewAlerter(rs, alerts, n, AlerterConfig{AlertOnRecovery: true})
+	a.Targets = &staticTargets{t: []*domain.Target{{ID: "A", URL: "https://a"}}}
+
+	_ = a.scanOnce(context.Background())
+	_ = a.scanOnce(context.Background())
+	if n.n != 1 || n.titles[0] != "🟢 Target latency OK" || alerts.m["A"].Degraded {
+		t.Fatalf("want one latency OK alert and the degraded state cleared, got %v", n.titles)
+	}
+}
+
+// eventNotifier records structured events.
+type eventNotifier struct {
+	memNotifier
+	events []notify.Event
+}
+
+func (e *eventNotifier) Notify(ctx context.Context, ev notify.Event) error {
+	e.events = append(e.events, ev)
+	return nil
+}
+
+func TestAlerter_ResolvesPagesWithoutRecoveryAlerts(t *testing.T) {
+	results := &fakeResults{rows: []repo.LatestRow{row("A", "https://a", true, intp(200), 50)}}
+	alerts := &memAlerts{m: map[string]repo.AlertRecord{"A": {TargetID: "A", LastState: false}}}
+	n := &eventNotifier{}
+	a := NewAlerter(results, alerts, n, AlerterConfig{AlertOnRecovery: false})
+
+	_ = a.scanOnce(context.Background())
+	if len(n.events) != 1 || n.events[0].Kind != notify.KindRecovered || !n.events[0].ResolveOnly {
+		t.Fatalf("want one resolve-only recovery, got %+v", n.events)
+	}
+	if rec := alerts.m["A"]; !rec.LastState || rec.LastSentAt != nil {
+		t.Fatalf("want UP recorded without a send time, got %+v", rec)
+	}
+}
+
+func TestAlerter_EventCarriesTargetPreviousStateAndIncident(t *testing.T) {
+	st := memory.New()
+	ctx := context.Background()
+	_ = st.Add(ctx, &domain.Target{ID: "A", URL: "https://a", HTTP: &domain.HTTPOptions{BearerToken: "secret"}})
+	cr := &domain.CheckResult{TargetID: "A", Up: false, Reason: "503", CheckedAt: time.Now().UTC()}
+	_ = st.Append(ctx, cr)
+	_ = st.TrackIncident(ctx, cr)
+	_ = st.Set(ctx, "A", true, time.Time{})
+
+	n := &eventNotifier{}
+	a := NewAlerter(st, st, n, AlerterConfig{Cooldown: time.Minute})
+	a.Targets = st
+	a.Incidents = st
+	_ = a.scanOnce(ctx)
+
+	if len(n.events) != 1 || n.n != 0 {
+		t.Fatalf("want one structured event, got %d events / %d plain sends", len(n.events), n.n)
+	}
+	ev := n.events[0]
+	if ev.Kind != notify.KindDown || ev.PreviousState != "up" || ev.Result == nil || ev.Result.Reason != "503" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if ev.Target == nil || ev.Target.HTTP == nil || ev.Target.HTTP.BearerToken == "secret" {
+		t.Fatalf("target missing or not redacted: %+v", ev.Target)
+	}
+	if ev.Incident == nil || !ev.Incident.Open() {
+		t.Fatalf("want the open incident, got %+v", ev.Incident)
+	}
+}
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+)
+
+// dueItem is one target in the Rechecker's schedule.
+type dueItem struct {
+	target  *domain.Target
+	due     time.Time
+	index   int         // position in the heap, maintained by dueQueue
+	running atomic.Bool // a check for this target is in flight
+
+	mu     sync.Mutex // orders forget with the end of a running check
+	forget bool       // drop the target's metrics once the running check ends
+}
+
+// dueQueue is a min-heap of targets ordered by next due time (container/heap).
+type dueQueue []*dueItem
+
+func (q dueQueue) Len() int           { return len(q) }
+func (q dueQueue) Less(i, j int) bool { return q[i].due.Before(q[j].due) }
+
+func (q dueQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *dueQueue) Push(x any) {
+	it := x.(*dueItem)
+	it.index = len(*q)
+	*q = append(*q, it)
+}
+
+func (q *dueQueue) Pop() any {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	old[n-1] = nil
+	it.index = -1
+	*q = old[:n-1]
+	return it
+}
+
+// peek returns the item due soonest, or nil when empty.
+func (q dueQueue) peek() *dueItem {
+	if len(q) == 0 {
+		return nil
+	}
+	return q[0]
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/notify"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+// escalate notifies the next escalation tiers of open incidents nobody has
+// acknowledged. Only outages the alerter has announced (target recorded as
+// down, not flapping) escalate, and not while the target is paused for
+// planned work; tiers are timed from the incident start.
+func (a *Alerter) escalate(ctx context.Context, rows []repo.LatestRow, targets map[string]*domain.Target, now time.Time) {
+	if a.Escalation == nil || a.Incidents == nil {
+		return
+	}
+	open, err := a.Incidents.Incidents(ctx, repo.IncidentQuery{OpenOnly: true})
+	if err != nil {
+		a.Metrics.StoreError("incidents")
+		return
+	}
+	latest := make(map[string]repo.LatestRow, len(rows))
+	for _, r := range rows {
+		latest[r.TargetID] = r
+	}
+
+	for i := range open {
+		inc := &open[i]
+		id := string(inc.TargetID)
+		r, ok := latest[id]
+		if !ok || inc.AckedAt != nil {
+			continue
+		}
+		if t := targets[id]; a.Targets != nil && (t == nil || t.Paused) {
+			continue
+		}
+		rec, _ := a.alertDB.Get(ctx, id)
+		if rec == nil || rec.LastState || rec.Flapping {
+			continue
+		}
+
+		ev := a.event(ctx, notify.KindEscalated, "", "", r, rec, targets[id])
+		ev.Incident = inc
+		tiers := a.Escalation.Escalation(ev)
+		level := inc.Escalation
+		for level < len(tiers) && now.Sub(inc.StartedAt) >= tiers[level].After() {
+			level++
+			ev.Title = fmt.Sprintf("🚨 Outage ESCALATED (tier %d)", level+1)
+			ev.Text = fmt.Sprintf(
+				"URL: %s\nDown since: %s\nUnacknowledged for: %s\nReason: %s\nAcknowledge: POST /api/incidents/%d/ack",
+				r.URL, inc.StartedAt.Format(time.RFC3339), now.Sub(inc.StartedAt).Round(time.Minute), r.Reason, inc.ID,
+			)
+			a.Metrics.Notification(notify.KindEscalated, a.Escalation.NotifyTier(ctx, tiers[level-1], ev))
+		}
+		if level != inc.Escalation {
+			if err := a.Incidents.SetEscalation(ctx, inc.ID, level); err != nil {
+				a.Metrics.StoreError("set_escalation")
+			}
+		}
+	}
+}
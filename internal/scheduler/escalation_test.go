@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/notify"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+	"github.com/hamed0406/uptimechecker/internal/repo/memory"
+)
+
+// fakeEscalator has fixed tiers and records what each tier was sent.
+type fakeEscalator struct {
+	tiers []notify.EscalationTier
+	sent  []notify.Event
+}
+
+func (f *fakeEscalator) Escalation(ev notify.Event) []notify.EscalationTier { return f.tiers }
+
+func (f *fakeEscalator) NotifyTier(ctx context.Context, tier notify.EscalationTier, ev notify.Event) error {
+	f.sent = append(f.sent, ev)
+	return nil
+}
+
+func TestAlerter_EscalatesUnacknowledgedOutages(t *testing.T) {
+	st := memory.New()
+	ctx := context.Background()
+	started := time.Now().UTC().Add(-45 * time.Minute)
+	for _, id := range []string{"A", "B"} {
+		_ = st.Add(ctx, &domain.Target{ID: domain.TargetID(id), URL: "https://" + id})
+		cr := &domain.CheckResult{TargetID: domain.TargetID(id), Up: false, Reason: "503", CheckedAt: started}
+		_ = st.Append(ctx, cr)
+		_ = st.TrackIncident(ctx, cr)
+	}
+	// B's outage is acknowledged before the alerter runs.
+	open, _ := st.Incidents(ctx, repo.IncidentQuery{TargetID: "B", OpenOnly: true})
+	_, _ = st.AckIncident(ctx, open[0].ID, "alice", time.Now())
+
+	esc := &fakeEscalator{tiers: []notify.EscalationTier{
+		{AfterMinutes: 10, Notifiers: []string{"lead"}},
+		{AfterMinutes: 30, Notifiers: []string{"manager"}},
+		{AfterMinutes: 60, Notifiers: []string{"director"}},
+	}}
+	nt := &memNotifier{}
+	a := NewAlerter(st, st, nt, AlerterConfig{Cooldown: time.Hour})
+	a.Targets = st
+	a.Incidents = st
+	a.Escalation = esc
+
+	_ = a.scanOnce(ctx) // initial DOWN alerts, then A's two overdue tiers
+	if nt.n != 2 {
+		t.Fatalf("want 2 DOWN alerts, got %d", nt.n)
+	}
+	if len(esc.sent) != 2 {
+		t.Fatalf("want tiers 2 and 3 for A, got %d escalations", len(esc.sent))
+	}
+	for i, ev := range esc.sent {
+		if ev.Kind != notify.KindEscalated || ev.Target == nil || ev.Target.ID != "A" || ev.Incident == nil {
+			t.Fatalf("escalation %d: %+v", i, ev)
+		}
+	}
+	if esc.sent[1].Title != "🚨 Outage ESCALATED (tier 3)" {
+		t.Fatalf("title = %q", esc.sent[1].Title)
+	}
+	incA, _ := st.Incidents(ctx, repo.IncidentQuery{TargetID: "A", OpenOnly: true})
+	if incA[0].Escalation != 2 {
+		t.Fatalf("escalation level = %d", incA[0].Escalation)
+	}
+
+	_ = a.scanOnce(ctx) // tier 4 (60m) is not due yet
+	if len(esc.sent) != 2 {
+		t.Fatalf("want no repeat escalation, got %d", len(esc.sent))
+	}
+
+	// Acknowledging stops escalation even once the next tier is due.
+	_, _ = st.AckIncident(ctx, incA[0].ID, "bob", time.Now())
+	esc.tiers[2].AfterMinutes = 40
+	_ = a.scanOnce(ctx)
+	if len(esc.sent) != 2 {
+		t.Fatalf("acked outage escalated: %d", len(esc.sent))
+	}
+}
+
+func TestAlerter_NoEscalationBeforeDownIsAlerted(t *testing.T) {
+	st := memory.New()
+	ctx := context.Background()
+	cr := &domain.CheckResult{TargetID: "A", Up: false, CheckedAt: time.Now().Add(-time.Hour)}
+	_ = st.Add(ctx, &domain.Target{ID: "A", URL: "https://a", DownAfter: 3})
+	_ = st.Append(ctx, cr)
+	_ = st.TrackIncident(ctx, cr)
+
+	esc := &fakeEscalator{tiers: []notify.EscalationTier{{AfterMinutes: 1, Notifiers: []string{"lead"}}}}
+	a := NewAlerter(st, st, &memNotifier{}, AlerterConfig{Cooldown: time.Hour})
+	a.Targets = st
+	a.Incidents = st
+	a.Escalation = esc
+	_ = a.scanOnce(ctx) // one failure of the three needed: not down yet
+	if len(esc.sent) != 0 {
+		t.Fatalf("unconfirmed outage escalated: %+v", esc.sent)
+	}
+}
+
+func TestAlerter_NoEscalationWhilePaused(t *testing.T) {
+	st := memory.New()
+	ctx := context.Background()
+	cr := &domain.CheckResult{TargetID: "A", Up: false, CheckedAt: time.Now().Add(-time.Hour)}
+	_ = st.Add(ctx, &domain.Target{ID: "A", URL: "https://a"})
+	_ = st.Append(ctx, cr)
+	_ = st.TrackIncident(ctx, cr)
+
+	esc := &fakeEscalator{tiers: []notify.EscalationTier{{AfterMinutes: 10, Notifiers: []string{"lead"}}}}
+	nt := &memNotifier{}
+	a := NewAlerter(st, st, nt, AlerterConfig{Cooldown: time.Hour})
+	a.Targets = st
+	a.Incidents = st
+	a.Escalation = esc
+
+	// Paused for planned work after the outage was announced.
+	_ = st.Set(ctx, "A", false, time.Now())
+	_ = st.SetPaused(ctx, "A", true)
+	_ = a.scanOnce(ctx)
+	if len(esc.sent) != 0 {
+		t.Fatalf("paused target escalated: %+v", esc.sent)
+	}
+
+	_ = st.SetPaused(ctx, "A", false)
+	_ = a.scanOnce(ctx)
+	if len(esc.sent) != 1 {
+		t.Fatalf("want escalation once resumed, got %d", len(esc.sent))
+	}
+}
@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/metrics"
+	"github.com/hamed0406/uptimechecker/internal/notify"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+)
+
+type OutboxConfig struct {
+	// PollInterval is how often due notifications are looked for (default
+	// 5s); new ones are also delivered as soon as they are queued.
+	PollInterval time.Duration
+	// MaxAttempts is how many deliveries are tried before a notification is
+	// dead-lettered (<= 1 means a single try).
+	MaxAttempts int
+	// Backoff is the wait after the first failure, doubled after each
+	// further one up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+const (
+	// outboxLease is how long a claimed notification is hidden from other
+	// workers; a worker that dies mid-delivery is retried after it.
+	outboxLease = 2 * time.Minute
+	// outboxBatch caps the notifications claimed at once.
+	outboxBatch = 50
+)
+
+// Outbox queues alerts in a store, one notification per routed notifier,
+// and delivers them in the background with retries. It stands in for the
+// router as the alerter's notifier, so a failed send is retried rather than
+// lost and every attempt is recorded.
+type Outbox struct {
+	store  repo.OutboxStore
+	router *notify.Router
+	cfg    OutboxConfig
+	wake   chan struct{}
+
+	// Metrics is optional; set after construction.
+	Metrics *metrics.Metrics
+}
+
+func NewOutbox(store repo.OutboxStore, router *notify.Router, cfg OutboxConfig) *Outbox {
+	return &Outbox{
+		store:  store,
+		router: router,
+		cfg:    cfg,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Send has no target to route on and goes to the default notifiers.
+func (o *Outbox) Send(ctx context.Context, title, text string) error {
+	return o.Notify(ctx, notify.Event{Title: title, Text: text})
+}
+
+// Notify queues ev for each notifier its route selects. The error is only
+// about queueing; delivery failures are retried by Run.
+func (o *Outbox) Notify(ctx context.Context, ev notify.Event) error {
+	return o.enqueue(ctx, o.router.Route(ev), ev)
+}
+
+// Escalation uses the router's escalation policies.
+func (o *Outbox) Escalation(ev notify.Event) []notify.EscalationTier {
+	return o.router.Escalation(ev)
+}
+
+// NotifyTier queues ev for the tier's notifiers.
+func (o *Outbox) NotifyTier(ctx context.Context, tier notify.EscalationTier, ev notify.Event) error {
+	return o.enqueue(ctx, o.router.TierNotifiers(tier), ev)
+}
+
+func (o *Outbox) enqueue(ctx context.Context, names []string, ev notify.Event) error {
+	if len(names) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+	now := time.Now().UTC()
+	targetID := ""
+	if ev.Target != nil {
+		targetID = string(ev.Target.ID)
+	}
+	ns := make([]*repo.Notification, 0, len(names))
+	for _, name := range names {
+		ns = append(ns, &repo.Notification{
+			Notifier:  name,
+			Kind:      ev.Kind,
+			TargetID:  targetID,
+			Title:     ev.Title,
+			Payload:   payload,
+			CreatedAt: now,
+		})
+	}
+	if err := o.store.Enqueue(ctx, ns); err != nil {
+		o.Metrics.StoreError("enqueue_notification")
+		return err
+	}
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers due notifications until ctx is done.
+func (o *Outbox) Run(ctx context.Context) error {
+	every := o.cfg.PollInterval
+	if every <= 0 {
+		every = 5 * time.Second
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	o.deliverDue(ctx, time.Now().UTC())
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		case <-o.wake:
+		}
+		o.deliverDue(ctx, time.Now().UTC())
+	}
+}
+
+// deliverDue attempts every notification due at now, a batch at a time.
+// It claims again until nothing is left, since a delivery can unblock the
+// next notification for the same target and notifier; claimed rows are
+// leased past now, so each is tried at most once per call.
+func (o *Outbox) deliverDue(ctx context.Context, now time.Time) {
+	for ctx.Err() == nil {
+		due, err := o.store.ClaimDue(ctx, now, outboxLease, outboxBatch)
+		if err != nil {
+			o.Metrics.StoreError("claim_notifications")
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+		for _, n := range due {
+			o.deliver(ctx, n)
+		}
+	}
+}
+
+// deliver makes one attempt at n and records the outcome: sent, pending
+// with the next retry time, or dead once attempts run out.
+func (o *Outbox) deliver(ctx context.Context, n repo.Notification) {
+	retry, err := o.attempt(ctx, n)
+	at := time.Now().UTC()
+	a := repo.DeliveryAttempt{At: at}
+	status, result, next := repo.NotificationSent, "sent", at
+	if err != nil {
+		a.Error = attemptError(err)
+		status, result, next = repo.NotificationPending, "failed", at.Add(o.backoff(n.Attempts+1))
+		if !retry || n.Attempts+1 >= o.cfg.MaxAttempts {
+			status, result = repo.NotificationDead, "dead"
+		}
+	}
+	o.Metrics.Delivery(n.Notifier, result)
+	if err := o.store.RecordAttempt(ctx, n.ID, a, status, next); err != nil {
+		o.Metrics.StoreError("record_delivery")
+	}
+}
+
+// attempt delivers n once; retry is false when trying again cannot help.
+func (o *Outbox) attempt(ctx context.Context, n repo.Notification) (retry bool, err error) {
+	nt, ok := o.router.Notifier(n.Notifier)
+	if !ok {
+		return false, fmt.Errorf("notifier %q is no longer configured", n.Notifier)
+	}
+	var ev notify.Event
+	if err := json.Unmarshal(n.Payload, &ev); err != nil {
+		return false, fmt.Errorf("decode notification: %w", err)
+	}
+	return true, notify.Deliver(ctx, nt, ev)
+}
+
+// attemptError is err as logged for the API, without the request URL of
+// transport errors: webhook URLs often embed their credentials.
+func attemptError(err error) string {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return fmt.Sprintf("%s request failed: %v", ue.Op, ue.Err)
+	}
+	return err.Error()
+}
+
+// backoff is the wait before the attempt after the given number of failures.
+func (o *Outbox) backoff(failures int) time.Duration {
+	d := o.cfg.Backoff
+	for i := 1; i < failures && d < o.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	if o.cfg.MaxBackoff > 0 && d > o.cfg.MaxBackoff {
+		d = o.cfg.MaxBackoff
+	}
+	return d
+}
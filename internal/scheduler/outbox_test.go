@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/notify"
+	"github.com/hamed0406/uptimechecker/internal/repo"
+	"github.com/hamed0406/uptimechecker/internal/repo/memory"
+)
+
+func newTestOutbox(t *testing.T, named ...notify.Named) (*Outbox, *memory.Store) {
+	t.Helper()
+	r, err := notify.NewRouter(notify.RoutingConfig{}, named)
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	st := memory.New()
+	return NewOutbox(st, r, OutboxConfig{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Minute}), st
+}
+
+func TestOutbox_RetriesOnlyFailedNotifierThenDeadLetters(t *testing.T) {
+	ok, flaky := &memNotifier{}, &memNotifier{err: errors.New("503 from slack")}
+	ob, st := newTestOutbox(t,
+		notify.Named{Name: "email", Notifier: ok},
+		notify.Named{Name: "slack", Notifier: flaky},
+	)
+	ctx := context.Background()
+
+	ev := notify.Event{Kind: notify.KindDown, Title: "🔴 Target DOWN", Target: &domain.Target{ID: "A"}}
+	if err := ob.Notify(ctx, ev); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if ok.n+flaky.n != 0 {
+		t.Fatal("Notify must only queue")
+	}
+
+	for i := 0; i < 3; i++ {
+		ob.deliverDue(ctx, time.Now().Add(time.Duration(i)*time.Hour))
+	}
+	if ok.n != 1 || flaky.n != 3 {
+		t.Fatalf("want email once and slack 3 times, got %d and %d", ok.n, flaky.n)
+	}
+
+	sent, _ := st.Notifications(ctx, repo.NotificationQuery{Status: repo.NotificationSent})
+	if len(sent) != 1 || sent[0].Notifier != "email" || sent[0].TargetID != "A" || sent[0].Kind != notify.KindDown {
+		t.Fatalf("unexpected sent: %+v", sent)
+	}
+	dead, _ := st.Notifications(ctx, repo.NotificationQuery{Status: repo.NotificationDead})
+	if len(dead) != 1 || dead[0].Attempts != 3 || len(dead[0].Log) != 3 || dead[0].LastError != "503 from slack" {
+		t.Fatalf("unexpected dead letter: %+v", dead)
+	}
+
+	// Dead letters are not tried again.
+	ob.deliverDue(ctx, time.Now().Add(24*time.Hour))
+	if flaky.n != 3 {
+		t.Fatalf("dead letter retried: %d", flaky.n)
+	}
+}
+
+func TestOutbox_RecoversAfterTransientFailure(t *testing.T) {
+	nt := &memNotifier{err: errors.New("timeout")}
+	ob, st := newTestOutbox(t, notify.Named{Name: "slack", Notifier: nt})
+	ctx := context.Background()
+
+	if err := ob.Send(ctx, "title", "text"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	ob.deliverDue(ctx, time.Now())
+	// Not due again until the backoff has passed.
+	ob.deliverDue(ctx, time.Now())
+	if nt.n != 1 {
+		t.Fatalf("retried before backoff: %d", nt.n)
+	}
+
+	nt.err = nil
+	ob.deliverDue(ctx, time.Now().Add(time.Minute))
+	all, _ := st.Notifications(ctx, repo.NotificationQuery{})
+	if nt.n != 2 || len(all) != 1 || all[0].Status != repo.NotificationSent || all[0].SentAt == nil {
+		t.Fatalf("want sent on second attempt: sends=%d %+v", nt.n, all)
+	}
+	if all[0].Log[0].Error != "timeout" || all[0].Log[1].Error != "" {
+		t.Fatalf("unexpected delivery log: %+v", all[0].Log)
+	}
+}
+
+func TestOutbox_KeepsOrderPerTargetAndNotifier(t *testing.T) {
+	nt := &memNotifier{err: errors.New("503 from pagerduty")}
+	ob, _ := newTestOutbox(t, notify.Named{Name: "pagerduty", Notifier: nt})
+	ctx := context.Background()
+	down := notify.Event{Kind: notify.KindDown, Title: "DOWN", Target: &domain.Target{ID: "A"}}
+	up := notify.Event{Kind: notify.KindRecovered, Title: "RECOVERED", Target: &domain.Target{ID: "A"}}
+	other := notify.Event{Kind: notify.KindDown, Title: "B DOWN", Target: &domain.Target{ID: "B"}}
+
+	_ = ob.Notify(ctx, down)
+	ob.deliverDue(ctx, time.Now()) // DOWN fails and backs off
+	nt.err = nil
+	_ = ob.Notify(ctx, up)
+	_ = ob.Notify(ctx, other)
+	ob.deliverDue(ctx, time.Now())
+	if want := []string{"DOWN", "B DOWN"}; !reflect.DeepEqual(nt.titles, want) {
+		t.Fatalf("RECOVERED must wait for the DOWN retry: got %v, want %v", nt.titles, want)
+	}
+
+	ob.deliverDue(ctx, time.Now().Add(time.Hour))
+	if want := []string{"DOWN", "B DOWN", "DOWN", "RECOVERED"}; !reflect.DeepEqual(nt.titles, want) {
+		t.Fatalf("got %v, want %v", nt.titles, want)
+	}
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	ob := &Outbox{cfg: OutboxConfig{Backoff: time.Second, MaxBackoff: 5 * time.Second}}
+	for failures, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 40: 5 * time.Second} {
+		if got := ob.backoff(failures); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestOutbox_AttemptErrorHidesURL(t *testing.T) {
+	err := fmt.Errorf("slack: %w", &url.Error{Op: "Post", URL: "https://hooks.slack.com/services/T0/B0/secret", Err: errors.New("timeout")})
+	if got := attemptError(err); strings.Contains(got, "secret") || !strings.Contains(got, "timeout") {
+		t.Fatalf("attemptError = %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"container/heap"
 	"context"
 	"sync"
 	"time"
@@ -8,18 +9,33 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/events"
+	"github.com/hamed0406/uptimechecker/internal/metrics"
 	"github.com/hamed0406/uptimechecker/internal/probe"
 	"github.com/hamed0406/uptimechecker/internal/repo"
 )
 
+// maxRefresh bounds how long a newly added or edited target waits before the
+// scheduler picks it up.
+const maxRefresh = 30 * time.Second
+
 type Rechecker struct {
 	Logger      *zap.Logger
 	Targets     repo.TargetStore
 	Results     repo.ResultStore
 	Checker     probe.Checker
-	Interval    time.Duration
-	Timeout     time.Duration
+	Interval    time.Duration // default per-target interval
+	Timeout     time.Duration // default per-target timeout
 	Concurrency int
+	Refresh     time.Duration      // how often the target list is re-read
+	Incidents   repo.IncidentStore // optional; opened/closed from confirmed results
+	Metrics     *metrics.Metrics   // optional
+	Events      *events.Bus        // optional; every result is published here
+	// DownAfter / UpAfter are the default streaks that open / close an
+	// incident, as in AlerterConfig; set them to the alerter's so incidents
+	// match the DOWN and RECOVERED alerts.
+	DownAfter int
+	UpAfter   int
 }
 
 func NewRechecker(
@@ -40,6 +56,10 @@ func NewRechecker(
 	if timeout <= 0 {
 		timeout = 10 * time.Second
 	}
+	refresh := interval
+	if refresh > maxRefresh {
+		refresh = maxRefresh
+	}
 	return &Rechecker{
 		Logger:      logger,
 		Targets:     ts,
@@ -48,10 +68,14 @@ func NewRechecker(
 		Interval:    interval,
 		Timeout:     timeout,
 		Concurrency: concurrency,
+		Refresh:     refresh,
 	}
 }
 
-// Run starts the loop. It does an immediate pass, then runs each tick.
+// Run schedules every target on its own interval. Targets are kept in a
+// min-heap keyed by next due time; the loop sleeps until the earliest one is
+// due (or the target list needs refreshing), runs what is due and pushes each
+// target back with its next due time. New targets are checked immediately.
 // Stops when ctx is cancelled.
 func (r *Rechecker) Run(ctx context.Context) {
 	if r.Interval == 0 {
@@ -59,75 +83,230 @@ func (r *Rechecker) Run(ctx context.Context) {
 		r.Logger.Info("rechecker_disabled")
 		return
 	}
-	t := time.NewTicker(r.Interval)
-	defer t.Stop()
 
-	// immediate pass
-	r.runOnce(ctx)
+	q := &dueQueue{}
+	byID := make(map[domain.TargetID]*dueItem)
+	sem := make(chan struct{}, r.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var nextRefresh time.Time
 
 	for {
+		now := time.Now()
+		if !now.Before(nextRefresh) {
+			r.syncTargets(ctx, q, byID, now)
+			nextRefresh = now.Add(r.Refresh)
+			r.Metrics.SetQueueDepth(q.Len())
+		}
+
+		for it := q.peek(); it != nil && !it.due.After(now); it = q.peek() {
+			r.dispatch(ctx, it, sem, &wg)
+			it.due = nextDue(it.due, it.target.Interval(r.Interval), now)
+			heap.Fix(q, 0)
+		}
+		r.Metrics.ObservePass(time.Since(now))
+
+		wait := nextRefresh.Sub(now)
+		if it := q.peek(); it != nil && it.due.Sub(now) < wait {
+			wait = it.due.Sub(now)
+		}
+		timer.Reset(wait)
+
 		select {
 		case <-ctx.Done():
 			r.Logger.Info("rechecker_stopped")
 			return
-		case <-t.C:
-			r.runOnce(ctx)
+		case <-timer.C:
 		}
 	}
 }
 
-func (r *Rechecker) runOnce(ctx context.Context) {
+// syncTargets reconciles the heap with the store: new (or resumed) targets
+// become due now, removed or paused ones are dropped and edited ones pick up
+// their new settings. The metrics of dropped targets, and of targets whose
+// URL changed, are forgotten once any running check of theirs has finished.
+func (r *Rechecker) syncTargets(ctx context.Context, q *dueQueue, byID map[domain.TargetID]*dueItem, now time.Time) {
 	ts, err := r.Targets.List(ctx)
 	if err != nil {
 		r.Logger.Warn("rechecker_list_error", zap.Error(err))
+		r.Metrics.StoreError("list_targets")
 		return
 	}
-	if len(ts) == 0 {
+
+	seen := make(map[domain.TargetID]bool, len(ts))
+	for _, t := range ts {
+		if t.Paused {
+			continue
+		}
+		seen[t.ID] = true
+		it, ok := byID[t.ID]
+		if !ok {
+			it = &dueItem{target: t, due: now}
+			byID[t.ID] = it
+			heap.Push(q, it)
+			continue
+		}
+		// interval shortened: don't make the target wait out the old one
+		if latest := now.Add(t.Interval(r.Interval)); it.due.After(latest) {
+			it.due = latest
+			heap.Fix(q, it.index)
+		}
+		if it.target.URL != t.URL {
+			r.forgetMetrics(it)
+		}
+		it.target = t
+	}
+	for id, it := range byID {
+		if !seen[id] {
+			heap.Remove(q, it.index)
+			delete(byID, id)
+			r.forgetMetrics(it)
+		}
+	}
+}
+
+// forgetMetrics drops the target's series now, or when its running check
+// finishes, since that check would otherwise record them again.
+func (r *Rechecker) forgetMetrics(it *dueItem) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if it.running.Load() {
+		it.forget = true
 		return
 	}
+	r.Metrics.ForgetTarget(string(it.target.ID))
+}
 
-	sem := make(chan struct{}, r.Concurrency)
-	var wg sync.WaitGroup
+// checkDone marks the check of target id as finished, forgetting its
+// metrics if syncTargets asked for that meanwhile.
+func (r *Rechecker) checkDone(it *dueItem, id domain.TargetID) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if it.forget {
+		it.forget = false
+		r.Metrics.ForgetTarget(string(id))
+	}
+	it.running.Store(false)
+}
+
+// dispatch runs one check in the background, bounded by sem. A target whose
+// previous check is still running is skipped for this round.
+func (r *Rechecker) dispatch(ctx context.Context, it *dueItem, sem chan struct{}, wg *sync.WaitGroup) {
+	if !it.running.CompareAndSwap(false, true) {
+		r.Logger.Debug("rechecker_still_running", zap.String("target_id", string(it.target.ID)))
+		return
+	}
+	t := it.target
+	wg.Add(1)
+	r.Metrics.CheckStarted()
+	go func() {
+		defer wg.Done()
+		defer r.checkDone(it, t.ID)
+		defer r.Metrics.CheckFinished()
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		defer func() { <-sem }()
+		r.checkOne(ctx, t)
+	}()
+}
 
-	for _, tgt := range ts {
-		t := tgt // avoid loop var capture
-		sem <- struct{}{}
-		wg.Add(1)
-		go func() {
-			defer func() { <-sem }()
-			defer wg.Done()
-
-			cctx, cancel := context.WithTimeout(ctx, r.Timeout)
-			defer cancel()
-
-			out := r.Checker.Check(cctx, t.URL)
-
-			cr := &domain.CheckResult{
-				TargetID:   t.ID,
-				Up:         out.Success,
-				HTTPStatus: out.StatusCode, // <-- now captured
-				LatencyMS:  out.LatencyMS,
-				Reason:     out.Message,
-				CheckedAt:  time.Now().UTC(),
-			}
-			if err := r.Results.Append(ctx, cr); err != nil {
-				r.Logger.Warn("rechecker_append_error",
-					zap.String("target_id", string(t.ID)),
-					zap.String("url", t.URL),
-					zap.Error(err),
-				)
-			} else {
-				r.Logger.Debug("rechecker_checked",
-					zap.String("target_id", string(t.ID)),
-					zap.String("url", t.URL),
-					zap.Int("status", out.StatusCode),
-					zap.Bool("up", out.Success),
-					zap.Float64("latency_ms", out.LatencyMS),
-					zap.String("reason", out.Message),
-				)
-			}
-		}()
-	}
-
-	wg.Wait()
+// nextDue advances due by interval, skipping missed slots rather than
+// bursting to catch up.
+func nextDue(due time.Time, interval time.Duration, now time.Time) time.Time {
+	next := due.Add(interval)
+	if !next.After(now) {
+		next = now.Add(interval)
+	}
+	return next
+}
+
+func (r *Rechecker) checkOne(ctx context.Context, t *domain.Target) {
+	cctx, cancel := context.WithTimeout(ctx, t.Timeout(r.Timeout))
+	defer cancel()
+
+	start := time.Now()
+	out := r.Checker.Check(probe.WithHTTPOptions(cctx, t.HTTP), t.URL)
+	r.Metrics.ObserveCheck(string(t.ID), t.URL, out.Success, out.Degraded, out.StatusCode, out.LatencyMS, time.Since(start))
+
+	cr := &domain.CheckResult{
+		TargetID:   t.ID,
+		Up:         out.Success,
+		HTTPStatus: out.StatusCode, // <-- now captured
+		LatencyMS:  out.LatencyMS,
+		Reason:     out.Message,
+		CheckedAt:  time.Now().UTC(),
+		Degraded:   out.Degraded,
+		Cert:       out.TLS,
+	}
+	if err := r.Results.Append(ctx, cr); err != nil {
+		r.Metrics.StoreError("append_result")
+		r.Logger.Warn("rechecker_append_error",
+			zap.String("target_id", string(t.ID)),
+			zap.String("url", t.URL),
+			zap.Error(err),
+		)
+	} else {
+		r.Logger.Debug("rechecker_checked",
+			zap.String("target_id", string(t.ID)),
+			zap.String("url", t.URL),
+			zap.Int("status", out.StatusCode),
+			zap.Bool("up", out.Success),
+			zap.Float64("latency_ms", out.LatencyMS),
+			zap.String("reason", out.Message),
+		)
+	}
+	r.Events.PublishResult(cr)
+
+	if r.Incidents != nil {
+		if err := r.trackIncident(ctx, t, cr); err != nil {
+			r.Metrics.StoreError("track_incident")
+			r.Logger.Warn("rechecker_incident_error",
+				zap.String("target_id", string(t.ID)),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// trackIncident folds cr into t's incidents once its state is confirmed by
+// the same streak the alerter waits for. The result completing the streak
+// brings in the whole streak, so an incident starts at its first failure and
+// ends at its first success; unconfirmed results are left out.
+func (r *Rechecker) trackIncident(ctx context.Context, t *domain.Target, cr *domain.CheckResult) error {
+	n := streakNeeded(t, cr.Up, r.DownAfter, r.UpAfter)
+	if n <= 1 {
+		return r.Incidents.TrackIncident(ctx, cr)
+	}
+	// One more than the streak tells whether cr completes it or extends it.
+	page, err := r.Results.History(ctx, repo.HistoryQuery{TargetID: string(t.ID), Limit: n + 1})
+	if err != nil {
+		return err
+	}
+	streak := 0
+	for _, res := range page.Results {
+		if res.Up != cr.Up {
+			break
+		}
+		streak++
+	}
+	switch {
+	case streak < n:
+		return nil
+	case streak > n:
+		return r.Incidents.TrackIncident(ctx, cr)
+	}
+	for i := n - 1; i > 0; i-- { // oldest first; [0] is cr
+		res := page.Results[i]
+		prev := &domain.CheckResult{TargetID: res.TargetID, Up: res.Up, Reason: res.Reason, CheckedAt: res.CheckedAt}
+		if err := r.Incidents.TrackIncident(ctx, prev); err != nil {
+			return err
+		}
+	}
+	return r.Incidents.TrackIncident(ctx, cr)
 }
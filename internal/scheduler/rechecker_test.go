@@ -2,6 +2,9 @@ package scheduler
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -9,8 +12,11 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/hamed0406/uptimechecker/internal/domain"
+	"github.com/hamed0406/uptimechecker/internal/events"
+	"github.com/hamed0406/uptimechecker/internal/metrics"
 	"github.com/hamed0406/uptimechecker/internal/probe"
 	"github.com/hamed0406/uptimechecker/internal/repo"
+	"github.com/hamed0406/uptimechecker/internal/repo/memory"
 )
 
 // --- fakes ---
@@ -21,6 +27,12 @@ type fakeTargets struct {
 }
 
 func (f *fakeTargets) Add(ctx context.Context, t *domain.Target) error { return nil }
+func (f *fakeTargets) Find(ctx context.Context, id domain.TargetID) (*domain.Target, error) {
+	return nil, nil
+}
+func (f *fakeTargets) Update(ctx context.Context, t *domain.Target) error     { return nil }
+func (f *fakeTargets) Delete(ctx context.Context, id domain.TargetID) error   { return nil }
+func (f *fakeTargets) SetPaused(context.Context, domain.TargetID, bool) error { return nil }
 func (f *fakeTargets) List(ctx context.Context) ([]*domain.Target, error) {
 	f.once.Do(func() {
 		f.t = []*domain.Target{{
@@ -58,6 +70,18 @@ func (f *fakeResults) Latest(ctx context.Context) ([]repo.LatestRow, error) {
 	return nil, nil
 }
 
+func (f *fakeResults) History(ctx context.Context, q repo.HistoryQuery) (repo.HistoryPage, error) {
+	return repo.HistoryPage{}, nil
+}
+
+func (f *fakeResults) Uptime(ctx context.Context, targetID string, from, to time.Time) (domain.UptimeReport, error) {
+	return domain.UptimeReport{}, nil
+}
+
+func (f *fakeResults) DailyUptime(ctx context.Context, targetID string, from time.Time, days int) ([]domain.UptimeReport, error) {
+	return nil, nil
+}
+
 type alwaysOK struct{}
 
 func (a *alwaysOK) Check(ctx context.Context, target string) probe.CheckResult {
@@ -107,3 +131,195 @@ func TestRechecker_RunOnceViaLoop_AppendsResult(t *testing.T) {
 		t.Fatalf("unexpected last result: %+v", last)
 	}
 }
+
+// staticTargets reuses fakeTargets' no-op writes but lists a fixed set.
+type staticTargets struct {
+	fakeTargets
+	t []*domain.Target
+}
+
+func (s *staticTargets) List(ctx context.Context) ([]*domain.Target, error) {
+	return s.t, nil
+}
+
+type countingResults struct {
+	fakeResults
+	byTarget map[domain.TargetID]int
+}
+
+func (c *countingResults) Append(ctx context.Context, cr *domain.CheckResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byTarget[cr.TargetID]++
+	return nil
+}
+
+func TestRechecker_PerTargetInterval(t *testing.T) {
+	tstore := &staticTargets{t: []*domain.Target{
+		{ID: "fast", URL: "https://fast", IntervalMS: 10},
+		{ID: "slow", URL: "https://slow"}, // falls back to the default interval
+		{ID: "paused", URL: "https://paused", IntervalMS: 10, Paused: true},
+	}}
+	rstore := &countingResults{byTarget: map[domain.TargetID]int{}}
+
+	rc := NewRechecker(zap.NewNop(), tstore, rstore, &alwaysOK{}, time.Hour, 200*time.Millisecond, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+	rc.Run(ctx)
+
+	rstore.mu.Lock()
+	defer rstore.mu.Unlock()
+	if n := rstore.byTarget["fast"]; n < 3 {
+		t.Fatalf("want fast target checked repeatedly, got %d", n)
+	}
+	if n := rstore.byTarget["slow"]; n != 1 {
+		t.Fatalf("want slow target checked once, got %d", n)
+	}
+	if n := rstore.byTarget["paused"]; n != 0 {
+		t.Fatalf("want paused target skipped, got %d", n)
+	}
+}
+
+func TestNextDue_SkipsMissedSlots(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := nextDue(base, time.Minute, base.Add(10*time.Second)); !got.Equal(base.Add(time.Minute)) {
+		t.Fatalf("on time: got %v", got)
+	}
+	now := base.Add(5 * time.Minute)
+	if got := nextDue(base, time.Minute, now); !got.Equal(now.Add(time.Minute)) {
+		t.Fatalf("behind: got %v", got)
+	}
+}
+
+func TestRechecker_PublishesResults(t *testing.T) {
+	tstore := &staticTargets{t: []*domain.Target{{ID: "a", URL: "https://a"}}}
+	rc := NewRechecker(zap.NewNop(), tstore, &fakeResults{}, &alwaysOK{}, time.Hour, 200*time.Millisecond, 1)
+	rc.Events = events.NewBus()
+	evs, unsub := rc.Events.Subscribe(4)
+	defer unsub()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rc.Run(ctx)
+
+	select {
+	case ev := <-evs:
+		if ev.Type != events.TypeResult || ev.TargetID != "a" || ev.Result == nil || !ev.Result.Up {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no result published")
+	}
+}
+
+// blockingChecker signals each check it starts and waits for release.
+type blockingChecker struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingChecker) Check(ctx context.Context, target string) probe.CheckResult {
+	b.started <- struct{}{}
+	<-b.release
+	return probe.CheckResult{Success: true, StatusCode: 200, LatencyMS: 1}
+}
+
+func TestRechecker_ForgetsMetricsOfTargetDeletedMidCheck(t *testing.T) {
+	store := memory.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = store.Add(ctx, &domain.Target{ID: "a", URL: "https://a.example"})
+
+	chk := &blockingChecker{started: make(chan struct{}), release: make(chan struct{})}
+	rc := NewRechecker(zap.NewNop(), store, store, chk, time.Hour, time.Second, 1)
+	rc.Refresh = 10 * time.Millisecond
+	rc.Metrics = metrics.New()
+	rc.Events = events.NewBus()
+	evs, unsub := rc.Events.Subscribe(1)
+	defer unsub()
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		rc.Metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return rec.Body.String()
+	}
+	done := make(chan struct{})
+	go func() { rc.Run(ctx); close(done) }()
+
+	select {
+	case <-chk.started:
+	case <-time.After(time.Second):
+		t.Fatal("check not started")
+	}
+	_ = store.Delete(ctx, "a")
+	time.Sleep(50 * time.Millisecond) // let the rechecker drop the target
+	close(chk.release)
+	select {
+	case <-evs: // the check has recorded its metrics
+	case <-time.After(time.Second):
+		t.Fatal("check did not finish")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for strings.Contains(scrape(), `target_id="a"`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("series of the deleted target survived its in-flight check:\n%s", scrape())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+// scriptedChecker reports the given states in order.
+type scriptedChecker struct{ ups []bool }
+
+func (s *scriptedChecker) Check(ctx context.Context, target string) probe.CheckResult {
+	up := s.ups[0]
+	s.ups = s.ups[1:]
+	if up {
+		return probe.CheckResult{Success: true, StatusCode: 200, Message: "200 OK"}
+	}
+	return probe.CheckResult{StatusCode: 503, Message: "503 Service Unavailable"}
+}
+
+func TestRechecker_IncidentsFollowConfirmedStreaks(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	tgt := &domain.Target{ID: "a", URL: "https://a"}
+	chk := &scriptedChecker{ups: []bool{true, false, true, false, false, false, true, true}}
+	rc := NewRechecker(zap.NewNop(), store, store, chk, time.Hour, time.Second, 1)
+	rc.Incidents = store
+	rc.DownAfter, rc.UpAfter = 2, 2
+
+	check := func(wantOpen, wantAll int) {
+		t.Helper()
+		rc.checkOne(ctx, tgt)
+		open, _ := store.Incidents(ctx, repo.IncidentQuery{TargetID: "a", OpenOnly: true})
+		all, _ := store.Incidents(ctx, repo.IncidentQuery{TargetID: "a"})
+		if len(open) != wantOpen || len(all) != wantAll {
+			t.Fatalf("%d open / %d incidents, want %d / %d", len(open), len(all), wantOpen, wantAll)
+		}
+	}
+	check(0, 0) // up
+	check(0, 0) // a single failure is a blip
+	check(0, 0) // up
+	check(0, 0) // down 1/2
+	check(1, 1) // down 2/2 opens the incident
+	check(1, 1) // down
+	check(1, 1) // up 1/2
+	check(0, 1) // up 2/2 closes it
+
+	page, _ := store.History(ctx, repo.HistoryQuery{TargetID: "a", Limit: 10})
+	res := page.Results // newest first: [7] is the first check
+	inc, _ := store.Incidents(ctx, repo.IncidentQuery{TargetID: "a"})
+	if got := inc[0]; !got.StartedAt.Equal(res[4].CheckedAt) || got.EndedAt == nil ||
+		!got.EndedAt.Equal(res[1].CheckedAt) || got.FailedChecks != 3 || got.FirstReason != "503 Service Unavailable" {
+		t.Fatalf("incident = %+v, want it to span the confirmed outage", got)
+	}
+
+	// a target's own down_after applies
+	tgt.DownAfter = 1
+	chk.ups = []bool{false}
+	check(1, 2)
+}
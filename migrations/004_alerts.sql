@@ -4,6 +4,3 @@ CREATE TABLE IF NOT EXISTS alerts (
   last_state   BOOLEAN NOT NULL,
   last_sent_at TIMESTAMPTZ
 );
-
--- +goose Down
-DROP TABLE IF EXISTS alerts;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Degraded = answered but needs attention (e.g. certificate about to expire).
+ALTER TABLE results ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT false;
+-- Leaf certificate seen during the TLS handshake (issuer, SANs, not_after, chain validity).
+ALTER TABLE results ADD COLUMN IF NOT EXISTS cert JSONB NULL;
+
+-- NotAfter of the certificate we last sent an "expiring" notice for.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS cert_notified_for TIMESTAMPTZ NULL;
@@ -0,0 +1,3 @@
+-- +goose Up
+-- Per-target HTTP check options (body assertions, read limit, ...), NULL = defaults.
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS http JSONB NULL;
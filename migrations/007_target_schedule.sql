@@ -0,0 +1,4 @@
+-- +goose Up
+-- Per-target check interval and timeout; 0 = use the global CHECK_INTERVAL_MS / HTTP_TIMEOUT_MS.
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS interval_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS timeout_ms  INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- One row per outage: opened by the first failed check, closed by the next success.
+CREATE TABLE IF NOT EXISTS incidents (
+  id            BIGSERIAL PRIMARY KEY,
+  target_id     TEXT NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+  started_at    TIMESTAMPTZ NOT NULL,
+  ended_at      TIMESTAMPTZ NULL,
+  first_reason  TEXT NOT NULL DEFAULT '',
+  failed_checks INTEGER NOT NULL DEFAULT 1
+);
+
+-- At most one open incident per target.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_open_per_target
+  ON incidents (target_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_target_started ON incidents (target_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_incidents_started_at     ON incidents (started_at DESC);
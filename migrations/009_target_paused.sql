@@ -0,0 +1,3 @@
+-- +goose Up
+-- Paused targets are kept but skipped by the scheduler.
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS paused BOOLEAN NOT NULL DEFAULT false;
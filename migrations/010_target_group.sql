@@ -0,0 +1,3 @@
+-- +goose Up
+-- Status page section a target is listed under ('' = default section).
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS group_name TEXT NOT NULL DEFAULT '';
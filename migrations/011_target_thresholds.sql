@@ -0,0 +1,4 @@
+-- +goose Up
+-- Consecutive failures/successes needed before alerting DOWN/RECOVERED (0 = global default).
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS down_after INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS up_after   INTEGER NOT NULL DEFAULT 0;
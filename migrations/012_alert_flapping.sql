@@ -0,0 +1,3 @@
+-- +goose Up
+-- Set while DOWN/RECOVERED notifications are suppressed for a flapping target.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS flapping BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+-- +goose Up
+-- Per-target latency threshold, e.g. {"percentile":95,"samples":5,"max_ms":800}.
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS latency JSONB NULL;
+-- Set while a latency-degraded notice is outstanding.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- +goose Up
+-- Free-form labels used for alert routing, e.g. {"team":"payments","env":"prod"}.
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS labels JSONB NULL;
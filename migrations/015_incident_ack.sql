@@ -0,0 +1,5 @@
+-- +goose Up
+-- Acknowledgement stops escalation; escalation_level counts the tiers notified.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS acked_at         TIMESTAMPTZ NULL;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS acked_by         TEXT NOT NULL DEFAULT '';
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS escalation_level INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Alerts waiting for (or done with) delivery, one row per notifier.
+CREATE TABLE IF NOT EXISTS notifications (
+  id              BIGSERIAL PRIMARY KEY,
+  notifier        TEXT NOT NULL,
+  kind            TEXT NOT NULL,
+  target_id       TEXT NOT NULL DEFAULT '',
+  title           TEXT NOT NULL DEFAULT '',
+  payload         JSONB NOT NULL,
+  status          TEXT NOT NULL DEFAULT 'pending',  -- pending | sent | dead
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  last_error      TEXT NOT NULL DEFAULT '',
+  log             JSONB NOT NULL DEFAULT '[]',      -- [{"at": ..., "error": ...}]
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  sent_at         TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due     ON notifications (next_attempt_at) WHERE status = 'pending';
+-- Per target and notifier, only the oldest pending row may be delivered.
+CREATE INDEX IF NOT EXISTS idx_notifications_order   ON notifications (target_id, notifier, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notifications_created ON notifications (created_at DESC);
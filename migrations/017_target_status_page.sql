@@ -0,0 +1,3 @@
+-- +goose Up
+-- Whether the target is listed on the public status page.
+ALTER TABLE targets ADD COLUMN IF NOT EXISTS status_page BOOLEAN NOT NULL DEFAULT false;
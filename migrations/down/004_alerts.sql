@@ -0,0 +1,2 @@
+-- Reverts ../004_alerts.sql.
+DROP TABLE IF EXISTS alerts;
@@ -0,0 +1,4 @@
+-- Reverts ../005_tls.sql.
+ALTER TABLE alerts DROP COLUMN IF EXISTS cert_notified_for;
+ALTER TABLE results DROP COLUMN IF EXISTS cert;
+ALTER TABLE results DROP COLUMN IF EXISTS degraded;
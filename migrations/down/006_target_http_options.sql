@@ -0,0 +1,2 @@
+-- Reverts ../006_target_http_options.sql.
+ALTER TABLE targets DROP COLUMN IF EXISTS http;
@@ -0,0 +1,3 @@
+-- Reverts ../007_target_schedule.sql.
+ALTER TABLE targets DROP COLUMN IF EXISTS timeout_ms;
+ALTER TABLE targets DROP COLUMN IF EXISTS interval_ms;
@@ -0,0 +1,2 @@
+-- Reverts ../008_incidents.sql.
+DROP TABLE IF EXISTS incidents;
@@ -0,0 +1,2 @@
+-- Reverts ../009_target_paused.sql.
+ALTER TABLE targets DROP COLUMN IF EXISTS paused;
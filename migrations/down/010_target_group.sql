@@ -0,0 +1,2 @@
+-- Reverts ../010_target_group.sql.
+ALTER TABLE targets DROP COLUMN IF EXISTS group_name;
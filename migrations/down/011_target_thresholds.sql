@@ -0,0 +1,3 @@
+-- Reverts ../011_target_thresholds.sql.
+ALTER TABLE targets DROP COLUMN IF EXISTS down_after;
+ALTER TABLE targets DROP COLUMN IF EXISTS up_after;
@@ -0,0 +1,2 @@
+-- Reverts ../012_alert_flapping.sql.
+ALTER TABLE alerts DROP COLUMN IF EXISTS flapping;
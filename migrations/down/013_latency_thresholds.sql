@@ -0,0 +1,3 @@
+-- Reverts ../013_latency_thresholds.sql.
+ALTER TABLE alerts DROP COLUMN IF EXISTS degraded;
+ALTER TABLE targets DROP COLUMN IF EXISTS latency;
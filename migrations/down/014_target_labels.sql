@@ -0,0 +1,2 @@
+-- Reverts ../014_target_labels.sql.
+ALTER TABLE targets DROP COLUMN IF EXISTS labels;
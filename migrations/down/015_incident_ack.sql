@@ -0,0 +1,4 @@
+-- Reverts ../015_incident_ack.sql.
+ALTER TABLE incidents DROP COLUMN IF EXISTS escalation_level;
+ALTER TABLE incidents DROP COLUMN IF EXISTS acked_by;
+ALTER TABLE incidents DROP COLUMN IF EXISTS acked_at;
@@ -0,0 +1,2 @@
+-- Reverts ../016_notification_outbox.sql.
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,2 @@
+-- Reverts ../017_target_status_page.sql.
+ALTER TABLE targets DROP COLUMN IF EXISTS status_page;